@@ -0,0 +1,76 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aic_package
+
+import (
+	"context"
+	"fmt"
+
+	imgManip "github.com/TheZoraiz/ascii-image-converter/image_manipulation"
+)
+
+/*
+ConvertToCells() works the same way as Convert(), except that each character of the ascii art is returned along with
+its colors instead of a string with escape codes, row by row. This lets programs such as TUIs place the ascii art
+in their own widgets. Each imgManip.Cell's Color is the one the character would be displayed with, which is
+Flags.FontColor for uncolored ascii art. Nothing is saved or written, and gifs aren't supported.
+*/
+func ConvertToCells(filePath string, flags Flags) ([][]imgManip.Cell, error) {
+	return ConvertToCellsWithContext(context.Background(), filePath, flags)
+}
+
+// Same as ConvertToCells(), except that the conversion returns ctx.Err() early if ctx is cancelled
+func ConvertToCellsWithContext(ctx context.Context, filePath string, flags Flags) ([][]imgManip.Cell, error) {
+
+	if err := setFlags(flags); err != nil {
+		return nil, err
+	}
+
+	in, err := openInput(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	if in.isGif {
+		return nil, fmt.Errorf("gifs can't be converted to cells")
+	}
+
+	imData, err := decodeImage(in.reader, in.imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	imgSet, _, err := imgManip.ConvertToAsciiPixelsContext(ctx, imData, dimensions, width, height, flipX, flipY, full, braille, pixelOptions())
+	if err != nil {
+		return nil, fmt.Errorf("can't convert %v: %w", in.imagePath, err)
+	}
+
+	cells := imgManip.GridCells(imgSet, asciiChars(imgSet))
+
+	if !colored && !grayscale && !colorBlocks() {
+		fgColor := [3]uint32{uint32(fontColor[0]), uint32(fontColor[1]), uint32(fontColor[2])}
+
+		for _, row := range cells {
+			for j := range row {
+				row[j].Color = fgColor
+			}
+		}
+	}
+
+	return cells, nil
+}
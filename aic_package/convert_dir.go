@@ -0,0 +1,196 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aic_package
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+/*
+ConvertDir() converts every image directly inside dirPath with the same flags, and returns their ascii art keyed by file
+name. This is useful for generating ascii thumbnails of a whole folder. Subdirectories, gifs and files that aren't images
+in a supported format are skipped, regardless of their extension, since formats are detected from file contents.
+
+Images are converted concurrently by Flags.BatchWorkers goroutines, each reading and converting one file at a time, so
+no more than that many files are held in memory at once. Flags for saving files work the same way as in Convert(), with
+each file being named after its image. Images that fail to convert are left out of the results, and their errors are
+returned together as a *BatchError in directory order.
+*/
+func ConvertDir(dirPath string, flags Flags) (map[string]string, error) {
+	return ConvertDirWithContext(context.Background(), dirPath, flags)
+}
+
+// Same as ConvertDir(), except that the conversion returns ctx.Err() early if ctx is cancelled
+func ConvertDirWithContext(ctx context.Context, dirPath string, flags Flags) (map[string]string, error) {
+
+	if err := setFlags(flags); err != nil {
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Mode().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	// Each result is written to its own index, so the order of names is kept regardless of which worker finishes first
+	results := make([]dirResult, len(names))
+
+	workers := batchWorkers
+	if workers > len(names) {
+		workers = len(names)
+	}
+
+	// Images are already converted concurrently, so the rest of Flags.MaxConcurrency is left for their pixels.
+	// Like every other global, it's only written here before workers start, and workers only read it
+	if workers > 0 {
+		pixelConcurrency = splitConcurrency(workers)
+	}
+
+	// Buffered to the number of workers, so files are only queued for reading after workers free up
+	jobs := make(chan int, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				r := &results[index]
+				r.asciiArt, r.ok, r.err = convertDirFile(ctx, filepath.Join(dirPath, names[index]))
+			}
+		}()
+	}
+
+	for index := range names {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- index
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	asciiArts := make(map[string]string)
+	batchErr := &BatchError{}
+
+	for index, r := range results {
+		if r.err != nil {
+			batchErr.Errors = append(batchErr.Errors, FileError{Name: names[index], Err: r.err})
+		} else if r.ok {
+			asciiArts[names[index]] = r.asciiArt
+		}
+	}
+
+	if len(batchErr.Errors) > 0 {
+		return asciiArts, batchErr
+	}
+
+	return asciiArts, nil
+}
+
+type dirResult struct {
+	asciiArt string
+	ok       bool
+	err      error
+}
+
+// Error of a single file converted by ConvertDir()
+type FileError struct {
+	Name string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return e.Name + ": " + e.Err.Error()
+}
+
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
+// Returned by ConvertDir() when some of its images fail to convert. Errors are in directory order
+type BatchError struct {
+	Errors []FileError
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+
+	messages := make([]string, len(e.Errors))
+	for i, fileErr := range e.Errors {
+		messages[i] = fileErr.Error()
+	}
+
+	return fmt.Sprintf("%v images failed to convert:\n%v", len(e.Errors), strings.Join(messages, "\n"))
+}
+
+// Returns the first error, so errors.Is() and errors.As() can be used on it
+func (e *BatchError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[0]
+}
+
+// Converts the file at imagePath for ConvertDir(), reporting false if it isn't a still image that can be decoded
+func convertDirFile(ctx context.Context, imagePath string) (string, bool, error) {
+
+	if err := ctx.Err(); err != nil {
+		return "", false, err
+	}
+
+	data, err := ioutil.ReadFile(imagePath)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to open file: %w", err)
+	}
+
+	// Gifs are played instead of being returned, so they don't fit here
+	if isGifHeader(data) {
+		return "", false, nil
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return "", false, nil
+	}
+
+	var sb strings.Builder
+	if err := convertReader(ctx, &sb, bytes.NewReader(data), imagePath, "", false); err != nil {
+		return "", false, err
+	}
+
+	return sb.String(), true, nil
+}
@@ -17,14 +17,14 @@ limitations under the License.
 package aic_package
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"image/color/palette"
 	"image/draw"
 	"image/gif"
+	"io"
 	"os"
-	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -41,26 +41,20 @@ type GifFrame struct {
 /*
 This function grabs each image frame from passed gif and turns it into ascii art. If SaveGifPath flag is passed,
 it'll turn each ascii art into an image instance of the same dimensions as the original gif and save them
-as an ascii art gif.
+as an ascii art gif. The ascii art is then played frame by frame on w.
 
 Multi-threading has been implemented in multiple places due to long execution time
 */
-func pathIsGif(gifPath, urlImgName string, pathIsURl bool, urlImgBytes []byte, localGif *os.File) error {
+func pathIsGif(ctx context.Context, w io.Writer, gifPath, urlImgName string, r io.Reader) error {
 
-	var (
-		originalGif *gif.GIF
-		err         error
-	)
-
-	if pathIsURl {
-		originalGif, err = gif.DecodeAll(bytes.NewReader(urlImgBytes))
-	} else {
-		originalGif, err = gif.DecodeAll(localGif)
-	}
+	originalGif, err := gif.DecodeAll(r)
 	if err != nil {
-		return fmt.Errorf("can't decode %v: %v", gifPath, err)
+		return fmt.Errorf("can't decode %v: %w", gifPath, err)
 	}
 
+	// Frames are composited so ones that only cover part of the gif are displayed correctly
+	frames, delays := imgManip.ExtractGifFrames(originalGif)
+
 	var (
 		asciiArtSet    = make([]string, len(originalGif.Image))
 		gifFramesSlice = make([]GifFrame, len(originalGif.Image))
@@ -68,53 +62,59 @@ func pathIsGif(gifPath, urlImgName string, pathIsURl bool, urlImgBytes []byte, l
 		counter             = 0
 		concurrentProcesses = 0
 		wg                  sync.WaitGroup
-		hostCpuCount        = runtime.NumCPU()
+		hostCpuCount        = maxConcurrency
 	)
 
-	fmt.Printf("Generating ascii art... 0%%\r")
+	// Frames are already converted concurrently, so the rest of Flags.MaxConcurrency is left for their pixels
+	frameOpts := pixelOptions()
+	frameOpts.MaxConcurrency = splitConcurrency(minInt(hostCpuCount, len(frames)))
 
-	// Get first frame of gif and its dimensions
-	firstGifFrame := originalGif.Image[0].SubImage(originalGif.Image[0].Rect)
-	firstGifFrameWidth := firstGifFrame.Bounds().Dx()
-	firstGifFrameHeight := firstGifFrame.Bounds().Dy()
+	fmt.Printf("Generating ascii art... 0%%\r")
 
 	// Multi-threaded loop to decrease execution time
-	for i, frame := range originalGif.Image {
+	for i, frame := range frames {
+
+		if ctx.Err() != nil {
+			break
+		}
 
 		wg.Add(1)
 		concurrentProcesses++
 
-		go func(i int, frame *image.Paletted) {
+		go func(i int, frame image.Image) {
 
-			frameImage := frame.SubImage(frame.Rect)
+			start := time.Now()
 
-			// If a frame is found that is smaller than the first frame, then this gif contains smaller subimages that are
-			// positioned inside the original gif. This behavior isn't supported by this app
-			if firstGifFrameWidth != frameImage.Bounds().Dx() || firstGifFrameHeight != frameImage.Bounds().Dy() {
-				fmt.Println("Error: GIF contains subimages smaller than default width and height\nProcess aborted because ascii-image-converter doesn't support subimage placement and transparency in GIFs\n")
+			imgSet, size, err := imgManip.ConvertToAsciiPixelsContext(ctx, frame, dimensions, width, height, flipX, flipY, full, braille, frameOpts)
+			if err != nil {
+				// Cancellation is returned once all running frames are done
+				if ctx.Err() != nil {
+					wg.Done()
+					return
+				}
+				fmt.Printf("Error: can't convert frame %v of %v: %v\n", i, gifPath, err)
 				os.Exit(0)
 			}
 
-			var imgSet [][]imgManip.AsciiPixel
+			asciiCharSet := borderAscii(padAscii(asciiChars(imgSet), true))
 
-			imgSet, err = imgManip.ConvertToAsciiPixels(frameImage, dimensions, width, height, flipX, flipY, full, braille)
-			if err != nil {
-				fmt.Println("Error:", err)
-				os.Exit(0)
-			}
+			reportProgress(Stats{
+				Path:     gifPath,
+				Frame:    i,
+				Frames:   len(frames),
+				Duration: time.Since(start),
+				Width:    size.Width,
+				Height:   size.Height,
+				Chars:    size.Width * size.Height,
+			})
 
-			var asciiCharSet [][]imgManip.AsciiChar
-			if braille {
-				asciiCharSet = imgManip.ConvertToBrailleChars(imgSet, negative, colored, colorBg, fontColor, threshold)
-			} else {
-				asciiCharSet = imgManip.ConvertToAsciiChars(imgSet, negative, colored, complex, colorBg, customMap, fontColor)
-			}
 			gifFramesSlice[i].asciiCharSet = asciiCharSet
-			gifFramesSlice[i].delay = originalGif.Delay[i]
+			gifFramesSlice[i].delay = delays[i]
 
-			ascii := flattenAscii(asciiCharSet, colored || grayscale, false)
+			ascii := flattenAscii(asciiCharSet, colored || grayscale || colorBlocks(), false)
 
-			asciiArtSet[i] = strings.Join(ascii, "\n")
+			indent := strings.Repeat(" ", centerColumns(len(asciiCharSet[0])))
+			asciiArtSet[i] = strings.Repeat("\n", centerRows(len(ascii))) + indent + strings.Join(ascii, "\n"+indent)
 
 			counter++
 			percentage := int((float64(counter) / float64(len(originalGif.Image))) * 100)
@@ -134,6 +134,10 @@ func pathIsGif(gifPath, urlImgName string, pathIsURl bool, urlImgBytes []byte, l
 	wg.Wait()
 	fmt.Printf("                              \r")
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Save ascii art as .gif file before displaying it, if --save-gif flag is passed
 	if saveGifPath != "" {
 
@@ -147,7 +151,7 @@ func pathIsGif(gifPath, urlImgName string, pathIsURl bool, urlImgBytes []byte, l
 
 		fullPathName, err := getFullSavePath(saveFileName, saveGifPath)
 		if err != nil {
-			return fmt.Errorf("can't save file: %v", err)
+			return fmt.Errorf("can't save file: %w", err)
 		}
 
 		// Initializing some constants for gif. Done outside loop to save execution
@@ -174,17 +178,19 @@ func pathIsGif(gifPath, urlImgName string, pathIsURl bool, urlImgBytes []byte, l
 		// Multi-threaded loop to decrease execution time
 		for i, gifFrame := range gifFramesSlice {
 
+			if ctx.Err() != nil {
+				break
+			}
+
 			wg.Add(1)
 			concurrentProcesses++
 
 			go func(i int, gifFrame GifFrame) {
 
-				img := originalGif.Image[i].SubImage(originalGif.Image[i].Rect)
-
 				tempImg, err := createGifFrameToSave(
 					gifFrame.asciiCharSet,
-					img,
-					colored || grayscale,
+					frames[i],
+					colored || grayscale || colorBlocks(),
 				)
 				if err != nil {
 					fmt.Println("Error:", err)
@@ -219,12 +225,16 @@ func pathIsGif(gifPath, urlImgName string, pathIsURl bool, urlImgBytes []byte, l
 
 		wg.Wait()
 
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		outGif.Image = palettedImageSlice
 		outGif.Delay = delaySlice
 
 		gifFile, err := os.OpenFile(fullPathName, os.O_WRONLY|os.O_CREATE, 0666)
 		if err != nil {
-			return fmt.Errorf("can't save file: %v", err)
+			return fmt.Errorf("can't save file: %w", err)
 		}
 		defer gifFile.Close()
 
@@ -233,25 +243,87 @@ func pathIsGif(gifPath, urlImgName string, pathIsURl bool, urlImgBytes []byte, l
 		fmt.Printf("                     \r")
 	}
 
-	// Display the gif
-	loopCount := 0
-	for {
-		for i, asciiFrame := range asciiArtSet {
+	return playGif(ctx, w, asciiArtSet, delays, gifPlayCount(originalGif.LoopCount))
+}
+
+/*
+Plays passed ascii art frames on w the given number of times, or forever if plays is 0, waiting for each frame's
+delay in 100ths of a second. In the terminal, the screen is cleared once and every frame after that is drawn over
+the previous one by moving the cursor back to the top, which avoids the flicker of clearing the screen each time.
+Flags.GifClear clears the screen before every frame instead. The cursor is hidden during playback and shown again
+once it ends or ctx is cancelled, leaving the last frame on screen.
+
+If Flags.FPS is set, frames are timed by a ticker at that rate and their delays are ignored.
+*/
+func playGif(ctx context.Context, w io.Writer, asciiArtSet []string, delays []int, plays int) error {
+
+	// Other writers might not be a terminal
+	isTerminal := w == os.Stdout
+
+	if isTerminal {
+		if !gifClear {
 			clearScreen()
-			fmt.Println(asciiFrame)
-			time.Sleep(time.Duration((time.Second * time.Duration(originalGif.Delay[i])) / 100))
 		}
+		fmt.Fprint(w, "\x1b[?25l")
+		defer fmt.Fprint(w, "\x1b[?25h")
+	}
 
-		// If gif is infinite loop
-		if originalGif.LoopCount == 0 {
-			continue
-		}
+	var ticker *time.Ticker
+	if fps > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / fps))
+		defer ticker.Stop()
+	}
 
-		loopCount++
-		if loopCount == originalGif.LoopCount {
-			break
+	for played := 0; plays == 0 || played < plays; played++ {
+		for i, asciiFrame := range asciiArtSet {
+			if isTerminal {
+				if gifClear {
+					clearScreen()
+				} else {
+					fmt.Fprint(w, "\x1b[H")
+				}
+			}
+			fmt.Fprintln(w, asciiFrame)
+
+			// No need to wait after the frame that's left on screen
+			if plays != 0 && played == plays-1 && i == len(asciiArtSet)-1 {
+				break
+			}
+
+			var next <-chan time.Time
+			if ticker != nil {
+				next = ticker.C
+			} else {
+				next = time.After(time.Duration((time.Second * time.Duration(delays[i])) / 100))
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-next:
+			}
 		}
 	}
 
 	return nil
 }
+
+/*
+Returns how many times the gif should be played, where 0 means forever. Flags.GifLoop and Flags.LoopCount take
+priority over the gif's own loop count, which counts the times the animation is restarted after the first play,
+with 0 meaning forever and -1 meaning it's only played once.
+*/
+func gifPlayCount(gifLoopCount int) int {
+	switch {
+	case gifLoop:
+		return 0
+	case loopCount >= 0:
+		return loopCount
+	case gifLoopCount == 0:
+		return 0
+	case gifLoopCount < 0:
+		return 1
+	default:
+		return gifLoopCount + 1
+	}
+}
@@ -17,56 +17,60 @@ limitations under the License.
 package aic_package
 
 import (
-	"bytes"
+	"bufio"
+	"context"
 	"fmt"
 	"image"
-	"os"
+	"io"
 	"strings"
+	"time"
 
 	imgManip "github.com/TheZoraiz/ascii-image-converter/image_manipulation"
+	"github.com/disintegration/imaging"
 )
 
-// This function decodes the passed image and returns an ascii art string, optionaly saving it as a .txt and/or .png file
-func pathIsImage(imagePath, urlImgName string, pathIsURl bool, urlImgBytes []byte, localImg *os.File) (string, error) {
+// This function decodes the passed image and writes its ascii art to w, optionaly saving it as a .txt and/or .png file
+func pathIsImage(ctx context.Context, w io.Writer, imagePath, urlImgName string, r io.Reader) error {
 
-	var (
-		imData image.Image
-		err    error
-	)
-
-	if pathIsURl {
-		imData, _, err = image.Decode(bytes.NewReader(urlImgBytes))
-	} else {
-		imData, _, err = image.Decode(localImg)
-	}
+	imData, err := decodeImage(r, imagePath)
 	if err != nil {
-		return "", fmt.Errorf("can't decode %v: %v", imagePath, err)
+		return err
 	}
 
-	imgSet, err := imgManip.ConvertToAsciiPixels(imData, dimensions, width, height, flipX, flipY, full, braille)
-	if err != nil {
-		return "", err
+	// Large images can be converted strip by strip, writing ascii art as it's generated
+	if tileRows > 0 && graphics == "" {
+		return writeAsciiTiles(ctx, w, imData, imagePath)
 	}
 
-	var asciiSet [][]imgManip.AsciiChar
+	start := time.Now()
 
-	if braille {
-		asciiSet = imgManip.ConvertToBrailleChars(imgSet, negative, colored, colorBg, fontColor, threshold)
-	} else {
-		asciiSet = imgManip.ConvertToAsciiChars(imgSet, negative, colored, complex, colorBg, customMap, fontColor)
+	imgSet, size, err := imgManip.ConvertToAsciiPixelsContext(ctx, imData, dimensions, width, height, flipX, flipY, full, braille, pixelOptions())
+	if err != nil {
+		return fmt.Errorf("can't convert %v: %w", imagePath, err)
 	}
 
+	asciiSet := borderAscii(padAscii(asciiChars(imgSet), true))
+
+	reportProgress(Stats{
+		Path:     imagePath,
+		Frames:   1,
+		Duration: time.Since(start),
+		Width:    size.Width,
+		Height:   size.Height,
+		Chars:    size.Width * size.Height,
+	})
+
 	// Save ascii art as .png image before printing it, if --save-img flag is passed
 	if saveImagePath != "" {
 		if err := createImageToSave(
 			asciiSet,
-			colored || grayscale,
+			colored || grayscale || colorBlocks(),
 			saveImagePath,
 			imagePath,
 			urlImgName,
 		); err != nil {
 
-			return "", fmt.Errorf("can't save file: %v", err)
+			return fmt.Errorf("can't save file: %w", err)
 		}
 	}
 
@@ -79,12 +83,125 @@ func pathIsImage(imagePath, urlImgName string, pathIsURl bool, urlImgBytes []byt
 			urlImgName,
 		); err != nil {
 
-			return "", fmt.Errorf("can't save file: %v", err)
+			return fmt.Errorf("can't save file: %w", err)
+		}
+	}
+
+	// Save ascii art as .html file before printing it, if --save-html flag is passed
+	if saveHtmlPath != "" {
+		if err := createHtmlToSave(
+			asciiSet,
+			colored || grayscale,
+			saveHtmlPath,
+			imagePath,
+			urlImgName,
+		); err != nil {
+
+			return fmt.Errorf("can't save file: %w", err)
 		}
 	}
 
-	ascii := flattenAscii(asciiSet, colored || grayscale, false)
-	result := strings.Join(ascii, "\n")
+	// Save ascii art as .json file before printing it, if --save-json flag is passed
+	if saveJsonPath != "" {
+		if err := createJsonToSave(
+			imgSet,
+			asciiSet,
+			saveJsonPath,
+			imagePath,
+			urlImgName,
+		); err != nil {
+
+			return fmt.Errorf("can't save file: %w", err)
+		}
+	}
+
+	// Save ascii art as .svg file before printing it, if --save-svg flag is passed
+	if saveSvgPath != "" {
+		if err := createSvgToSave(
+			asciiSet,
+			colored || grayscale,
+			saveSvgPath,
+			imagePath,
+			urlImgName,
+		); err != nil {
+
+			return fmt.Errorf("can't save file: %w", err)
+		}
+	}
+
+	// Display the image itself instead of ascii art, if --graphics flag is passed
+	if graphics != "" {
+		graphicsArt, err := graphicsOutput(imData)
+		if err != nil {
+			return fmt.Errorf("can't convert %v: %w", imagePath, err)
+		}
+
+		_, err = io.WriteString(w, graphicsArt)
+		return err
+	}
+
+	if _, err := io.WriteString(w, strings.Repeat("\n", centerRows(len(asciiSet)))); err != nil {
+		return err
+	}
+
+	return writeAscii(w, asciiSet, colored || grayscale || colorBlocks())
+}
+
+// Decodes the still image in r, with clearer errors for formats that can't be decoded
+func decodeImage(r io.Reader, imagePath string) (image.Image, error) {
+
+	bufReader := bufio.NewReader(r)
+
+	header, _ := bufReader.Peek(webpHeaderSize)
+
+	// The webp decoder only supports still images, so animated ones get a clearer error than "invalid format"
+	if isAnimatedWebp(header) {
+		return nil, fmt.Errorf("can't decode %v: animated webp images aren't supported", imagePath)
+	}
+
+	// Photos from phones are often stored sideways with an EXIF tag recording how to display them
+	imData, err := imaging.Decode(bufReader, imaging.AutoOrientation(!noAutoOrient))
+	if err != nil {
+		if format := heifFormat(header); format != "" {
+			return nil, fmt.Errorf("can't decode %v: %v images aren't supported", imagePath, format)
+		}
+		return nil, fmt.Errorf("can't decode %v: %w", imagePath, err)
+	}
+
+	return imData, nil
+}
+
+// Converts passed image in strips of Flags.TileRows rows and writes each to w as soon as it's done
+func writeAsciiTiles(ctx context.Context, w io.Writer, img image.Image, imagePath string) error {
+
+	first := true
+	start := time.Now()
+
+	size, err := imgManip.ConvertToAsciiPixelTiles(ctx, img, dimensions, width, height, flipX, flipY, full, braille, pixelOptions(), tileRows,
+		func(imgSet [][]imgManip.AsciiPixel) error {
+			if !first {
+				if _, err := io.WriteString(w, "\n"); err != nil {
+					return err
+				}
+			}
+			first = false
+
+			return writeAscii(w, padAscii(asciiChars(imgSet), false), colored || grayscale || colorBlocks())
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("can't convert %v: %w", imagePath, err)
+	}
 
-	return result, nil
+	// Strips are written as they're converted, so the duration includes writing them
+	reportProgress(Stats{
+		Path:     imagePath,
+		Frames:   1,
+		Duration: time.Since(start),
+		Width:    size.Width,
+		Height:   size.Height,
+		Chars:    size.Width * size.Height,
+	})
+
+	return nil
 }
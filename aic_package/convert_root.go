@@ -17,11 +17,19 @@ limitations under the License.
 package aic_package
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"fmt"
+	"image"
+	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path"
+	"runtime"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	// Image format initialization
 	_ "image/jpeg"
@@ -32,6 +40,7 @@ import (
 	_ "golang.org/x/image/tiff"
 	_ "golang.org/x/image/webp"
 
+	imgManip "github.com/TheZoraiz/ascii-image-converter/image_manipulation"
 	"github.com/asaskevich/govalidator"
 	"github.com/golang/freetype/truetype"
 )
@@ -40,26 +49,107 @@ import (
 // Can be sent directly to ConvertImage() for default ascii art
 func DefaultFlags() Flags {
 	return Flags{
-		Complex:             false,
-		Dimensions:          nil,
-		Width:               0,
-		Height:              0,
-		SaveTxtPath:         "",
-		SaveImagePath:       "",
-		SaveGifPath:         "",
-		Negative:            false,
-		Colored:             false,
-		CharBackgroundColor: false,
-		Grayscale:           false,
-		CustomMap:           "",
-		FlipX:               false,
-		FlipY:               false,
-		Full:                false,
-		FontFilePath:        "",
-		FontColor:           [3]int{255, 255, 255},
-		SaveBackgroundColor: [3]int{0, 0, 0},
-		Braille:             false,
-		Threshold:           128,
+		Complex:               false,
+		Dimensions:            nil,
+		Width:                 0,
+		Height:                0,
+		SaveTxtPath:           "",
+		SaveJsonPath:          "",
+		SaveImagePath:         "",
+		SaveGifPath:           "",
+		GifClear:              false,
+		GifLoop:               false,
+		LoopCount:             -1,
+		FPS:                   0,
+		SaveHtmlPath:          "",
+		HtmlFont:              "monospace",
+		SaveSvgPath:           "",
+		SvgCellSize:           [2]int{10, 20},
+		SvgFont:               "monospace",
+		Negative:              false,
+		Invert:                false,
+		Theme:                 "dark",
+		Colored:               false,
+		Gradient:              nil,
+		Rainbow:               "",
+		Quantize:              0,
+		QuantizeMethod:        "median-cut",
+		Palette:               "",
+		CustomPalette:         nil,
+		CharBackgroundColor:   false,
+		DualColor:             false,
+		Grayscale:             false,
+		CustomMap:             "",
+		ReverseMap:            false,
+		Coverage:              false,
+		AlphaThreshold:        0,
+		TransparentChar:       " ",
+		AlphaBackgroundColor:  [3]int{0, 0, 0},
+		AlphaWeighted:         false,
+		Checkerboard:          false,
+		CheckerColors:         nil,
+		CheckerSize:           4,
+		FlipX:                 false,
+		FlipY:                 false,
+		Full:                  false,
+		TileRows:              0,
+		Crop:                  nil,
+		Trim:                  false,
+		TrimTolerance:         10,
+		Blur:                  0,
+		Sharpen:               0,
+		NoAutoOrient:          false,
+		Rotate:                0,
+		RotateBackgroundColor: [3]int{0, 0, 0},
+		ResizeFilter:          "lanczos",
+		FontRatio:             2,
+		NoAspectCorrection:    false,
+		Fit:                   "stretch",
+		PadChar:               " ",
+		PadColor:              nil,
+		Border:                "",
+		BorderColor:           nil,
+		Center:                false,
+		CenterVertical:        false,
+		TerminalSize:          nil,
+		FontFilePath:          "",
+		FontColor:             [3]int{255, 255, 255},
+		SaveBackgroundColor:   [3]int{0, 0, 0},
+		Braille:               false,
+		BrailleDots:           8,
+		Threshold:             128,
+		Dithering:             0,
+		DitherMode:            "",
+		BayerSize:             4,
+		Gamma:                 1,
+		Brightness:            0,
+		Contrast:              1,
+		Saturation:            1,
+		Sepia:                 0,
+		Posterize:             0,
+		Equalize:              false,
+		Luminance:             "",
+		LuminanceWeights:      nil,
+		HighPrecision:         false,
+		LinearResize:          false,
+		HalfBlock:             false,
+		BgBlock:               false,
+		Quadrant:              false,
+		Sextant:               false,
+		Octant:                false,
+		EdgeMode:              "",
+		EdgeThreshold:         64,
+		EdgeLowThreshold:      0,
+		EdgeBlur:              1.4,
+		Graphics:              "",
+		ColorMode:             "truecolor",
+		ColorDistance:         "ciede2000",
+		URLTimeout:            30 * time.Second,
+		UserAgent:             "ascii-image-converter",
+		MaxDownloadSize:       50 << 20,
+		BatchWorkers:          0,
+		MaxConcurrency:        0,
+		Progress:              nil,
 	}
 }
 
@@ -67,8 +157,187 @@ func DefaultFlags() Flags {
 Convert() takes an image or gif path/url as its first argument
 and a aic_package.Flags literal as the second argument, with which it alters
 the returned ascii art string.
+
+If "-" is passed as the path, the image or gif is read from stdin instead.
 */
 func Convert(filePath string, flags Flags) (string, error) {
+	return ConvertWithContext(context.Background(), filePath, flags)
+}
+
+/*
+Same as Convert(), except that the conversion returns ctx.Err() early if ctx is cancelled. This covers fetching
+urls, converting each image or gif frame and displaying gifs, which would otherwise loop forever for infinite gifs.
+*/
+func ConvertWithContext(ctx context.Context, filePath string, flags Flags) (string, error) {
+	if filePath == stdinPath {
+		stdinBytes, err := readStdin()
+		if err != nil {
+			return "", err
+		}
+		return ConvertReaderWithContext(ctx, bytes.NewReader(stdinBytes), flags)
+	}
+
+	var sb strings.Builder
+
+	// Gifs are played on the terminal instead of being returned
+	var w io.Writer = &sb
+	if path.Ext(filePath) == ".gif" {
+		w = os.Stdout
+	}
+
+	err := ConvertToWriterWithContext(ctx, w, filePath, flags)
+	return sb.String(), err
+}
+
+/*
+ConvertToWriter() works the same way as Convert(), except that the ascii art is written to w line by line instead
+of being returned, so large ascii art can be streamed into files, network connections or buffers without being
+held in memory as a whole. Gifs are played on w frame by frame, where the screen is only cleared between
+frames if w is os.Stdout.
+*/
+func ConvertToWriter(w io.Writer, filePath string, flags Flags) error {
+	return ConvertToWriterWithContext(context.Background(), w, filePath, flags)
+}
+
+// Same as ConvertToWriter(), except that the conversion returns ctx.Err() early if ctx is cancelled
+func ConvertToWriterWithContext(ctx context.Context, w io.Writer, filePath string, flags Flags) error {
+
+	if err := setFlags(flags); err != nil {
+		return err
+	}
+
+	in, err := openInput(ctx, filePath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	return convertReader(ctx, w, in.reader, in.imagePath, in.urlImgName, in.isGif)
+}
+
+// Data of an image or gif read from a path, url or stdin
+type input struct {
+	reader     io.Reader
+	imagePath  string
+	urlImgName string
+	isGif      bool
+
+	// Only set for local files
+	file *os.File
+}
+
+func (in input) Close() {
+	if in.file != nil {
+		in.file.Close()
+	}
+}
+
+// Opens filePath the way Convert() does, whether it's a local file, a url or "-" for stdin
+func openInput(ctx context.Context, filePath string) (input, error) {
+
+	var (
+		reader     io.Reader
+		urlImgName string = ""
+		localFile  *os.File
+	)
+
+	if filePath == stdinPath {
+		stdinBytes, err := readStdin()
+		if err != nil {
+			return input{}, err
+		}
+		return input{bytes.NewReader(stdinBytes), readerImgName, readerImgName, isGifHeader(stdinBytes), nil}, nil
+	}
+
+	pathIsURl := govalidator.IsRequestURL(filePath)
+
+	// Different modes of reading data depending upon whether or not filePath is a url
+	if pathIsURl {
+		fmt.Printf("Fetching file from url...\r")
+
+		urlImgBytes, err := fetchURL(ctx, filePath)
+		if err != nil {
+			return input{}, err
+		}
+
+		reader = bytes.NewReader(urlImgBytes)
+		urlImgName = path.Base(filePath)
+		fmt.Printf("                          \r") // To erase "Fetching image from url..." text from terminal
+
+	} else {
+
+		var err error
+		localFile, err = os.Open(filePath)
+		if err != nil {
+			return input{}, fmt.Errorf("unable to open file: %w", err)
+		}
+
+		reader = localFile
+	}
+
+	return input{reader, filePath, urlImgName, path.Ext(filePath) == ".gif", localFile}, nil
+}
+
+/*
+ConvertReader() works the same way as Convert(), except that it decodes the image or gif from r instead of a
+path/url. This way, http response bodies, embedded files or in-memory buffers can be converted directly.
+Gifs are recognized from their header rather than a file extension.
+
+Since there is no file name to go by, saved files are named "image-ascii-art" with their respective extension.
+*/
+func ConvertReader(r io.Reader, flags Flags) (string, error) {
+	return ConvertReaderWithContext(context.Background(), r, flags)
+}
+
+// Same as ConvertReader(), except that the conversion returns ctx.Err() early if ctx is cancelled
+func ConvertReaderWithContext(ctx context.Context, r io.Reader, flags Flags) (string, error) {
+
+	if err := setFlags(flags); err != nil {
+		return "", err
+	}
+
+	bufReader := bufio.NewReader(r)
+
+	header, _ := bufReader.Peek(4)
+	isGif := isGifHeader(header)
+
+	var sb strings.Builder
+
+	// Gifs are played on the terminal instead of being returned
+	var w io.Writer = &sb
+	if isGif {
+		w = os.Stdout
+	}
+
+	err := convertReader(ctx, w, bufReader, readerImgName, readerImgName, isGif)
+	return sb.String(), err
+}
+
+// Name used in place of a file name for saved files and errors when converting from an io.Reader
+const readerImgName = "image"
+
+// Path that reads the image or gif from stdin
+const stdinPath = "-"
+
+// Reports whether data starts with a gif header. Both GIF87a and GIF89a headers start with GIF8
+func isGifHeader(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("GIF8"))
+}
+
+// Reads all of stdin, which needs to be read fully before the image format can be detected
+func readStdin() ([]byte, error) {
+	stdinBytes, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read stdin: %w", err)
+	}
+	if len(stdinBytes) == 0 {
+		return nil, fmt.Errorf("no input received from stdin")
+	}
+	return stdinBytes, nil
+}
+
+// Stores passed flags in their respective package-level variables, along with loading the font for saved files
+func setFlags(flags Flags) error {
 
 	if flags.Dimensions == nil {
 		dimensions = nil
@@ -79,78 +348,306 @@ func Convert(filePath string, flags Flags) (string, error) {
 	height = flags.Height
 	complex = flags.Complex
 	saveTxtPath = flags.SaveTxtPath
+	saveJsonPath = flags.SaveJsonPath
 	saveImagePath = flags.SaveImagePath
 	saveGifPath = flags.SaveGifPath
+	gifClear = flags.GifClear
+	gifLoop = flags.GifLoop
+	loopCount = flags.LoopCount
+	fps = flags.FPS
+	saveHtmlPath = flags.SaveHtmlPath
+	htmlFont = flags.HtmlFont
+	if htmlFont == "" {
+		htmlFont = "monospace"
+	}
+	saveSvgPath = flags.SaveSvgPath
+	svgCellSize = flags.SvgCellSize
+	if svgCellSize[0] < 1 || svgCellSize[1] < 1 {
+		svgCellSize = [2]int{10, 20}
+	}
+	svgFont = flags.SvgFont
+	if svgFont == "" {
+		svgFont = "monospace"
+	}
 	negative = flags.Negative
+	invert = flags.Invert
+	theme = flags.Theme
+	if lightTheme(theme) {
+		invert = !invert
+	}
 	colored = flags.Colored
+	useGradient = flags.Gradient != nil
+	if useGradient {
+		if len(flags.Gradient) != 6 {
+			return fmt.Errorf("gradient needs red, green and blue values of two colors")
+		}
+		for _, value := range flags.Gradient {
+			if value < 0 || value > 255 {
+				return fmt.Errorf("gradient color values must be between 0 and 255")
+			}
+		}
+
+		copy(gradient[0][:], flags.Gradient[:3])
+		copy(gradient[1][:], flags.Gradient[3:])
+		colored = true
+	}
+	quantize = flags.Quantize
+	quantizeMethod = flags.QuantizeMethod
+	colorPalette = flags.Palette
+	customPalette = nil
+	if flags.CustomPalette != nil {
+		if len(flags.CustomPalette) == 0 || len(flags.CustomPalette)%3 != 0 {
+			return fmt.Errorf("custom palette needs red, green and blue values of each color")
+		}
+		for i := 0; i < len(flags.CustomPalette); i += 3 {
+			customPalette = append(customPalette, [3]int{flags.CustomPalette[i], flags.CustomPalette[i+1], flags.CustomPalette[i+2]})
+		}
+	}
+	rainbow = flags.Rainbow
+	if rainbow != "" {
+		colored = true
+	}
 	colorBg = flags.CharBackgroundColor
+	dualColor = flags.DualColor
 	grayscale = flags.Grayscale
 	customMap = flags.CustomMap
+	reverseMap = flags.ReverseMap
+	alphaThreshold = flags.AlphaThreshold
+	alphaBgColor = flags.AlphaBackgroundColor
+	alphaWeighted = flags.AlphaWeighted
+	checkerboard = flags.Checkerboard
+	checkerColors = [2][3]int{{102, 102, 102}, {153, 153, 153}}
+	if flags.CheckerColors != nil {
+		if len(flags.CheckerColors) != 6 {
+			return fmt.Errorf("checkerboard needs red, green and blue values of two colors")
+		}
+		for _, value := range flags.CheckerColors {
+			if value < 0 || value > 255 {
+				return fmt.Errorf("checkerboard color values must be between 0 and 255")
+			}
+		}
+
+		copy(checkerColors[0][:], flags.CheckerColors[:3])
+		copy(checkerColors[1][:], flags.CheckerColors[3:])
+	}
+	checkerSize = flags.CheckerSize
+	transparentChar = flags.TransparentChar
+	if transparentChar == "" {
+		transparentChar = " "
+	}
 	flipX = flags.FlipX
 	flipY = flags.FlipY
 	full = flags.Full
+	resizeFilter = flags.ResizeFilter
+	fontRatio = flags.FontRatio
+	noAspectCorrection = flags.NoAspectCorrection
+	fit = flags.Fit
+	padChar = flags.PadChar
+	if padChar == "" {
+		padChar = " "
+	}
+	borderChars = nil
+	if flags.Border != "" {
+		chars, err := imgManip.BorderChars(flags.Border)
+		if err != nil {
+			return err
+		}
+		borderChars = &chars
+	}
+	borderColor = nil
+	if flags.BorderColor != nil {
+		if len(flags.BorderColor) != 3 {
+			return fmt.Errorf("border color needs red, green and blue values")
+		}
+		var rgb [3]int
+		for i, value := range flags.BorderColor {
+			if value < 0 || value > 255 {
+				return fmt.Errorf("border color values must be between 0 and 255")
+			}
+			rgb[i] = value
+		}
+		borderColor = &rgb
+	}
+	center = flags.Center
+	centerVertical = flags.CenterVertical
+	padColor = nil
+	if flags.PadColor != nil {
+		if len(flags.PadColor) != 3 {
+			return fmt.Errorf("pad color needs red, green and blue values")
+		}
+		var rgb [3]int
+		for i, value := range flags.PadColor {
+			if value < 0 || value > 255 {
+				return fmt.Errorf("pad color values must be between 0 and 255")
+			}
+			rgb[i] = value
+		}
+		padColor = &rgb
+	}
+	terminalSize = [2]int{}
+	if flags.TerminalSize != nil {
+		if len(flags.TerminalSize) != 2 || flags.TerminalSize[0] < 1 || flags.TerminalSize[1] < 1 {
+			return fmt.Errorf("terminal size needs a width and height greater than 0")
+		}
+		terminalSize = [2]int{flags.TerminalSize[0], flags.TerminalSize[1]}
+	}
 	fontPath = flags.FontFilePath
 	fontColor = flags.FontColor
 	saveBgColor = flags.SaveBackgroundColor
 	braille = flags.Braille
+	brailleDots = flags.BrailleDots
 	threshold = flags.Threshold
-
-	// Declared at the start since some variables are initially used in conditional blocks
-	var (
-		localFile   *os.File
-		urlImgBytes []byte
-		urlImgName  string = ""
-		err         error
-	)
-
-	pathIsURl := govalidator.IsRequestURL(filePath)
-
-	// Different modes of reading data depending upon whether or not filePath is a url
-	if pathIsURl {
-		fmt.Printf("Fetching file from url...\r")
-
-		retrievedImage, err := http.Get(filePath)
-		if err != nil {
-			return "", fmt.Errorf("can't fetch content: %v", err)
+	dithering = flags.Dithering
+	ditherMode = flags.DitherMode
+	bayerSize = flags.BayerSize
+	gamma = flags.Gamma
+	brightness = flags.Brightness
+	contrast = flags.Contrast
+	saturation = flags.Saturation
+	sepia = flags.Sepia
+	posterize = flags.Posterize
+	equalize = flags.Equalize
+	luminance = flags.Luminance
+	highPrecision = flags.HighPrecision
+	linearResize = flags.LinearResize
+	luminanceWeights = [3]float64{}
+	if flags.LuminanceWeights != nil {
+		w := flags.LuminanceWeights
+		if len(w) != 3 || w[0] < 0 || w[1] < 0 || w[2] < 0 || w[0]+w[1]+w[2] == 0 {
+			return fmt.Errorf("luminance weights need red, green and blue values that aren't negative or all 0")
 		}
+		luminanceWeights = [3]float64{w[0], w[1], w[2]}
+	}
+	halfBlock = flags.HalfBlock
+	bgBlock = flags.BgBlock
+	quadrant = flags.Quadrant
+	sextant = flags.Sextant
+	octant = flags.Octant
+	edgeMode = flags.EdgeMode
+	edgeThreshold = flags.EdgeThreshold
+	edgeLowThreshold = flags.EdgeLowThreshold
+	edgeBlur = flags.EdgeBlur
+	graphics = flags.Graphics
 
-		urlImgBytes, err = ioutil.ReadAll(retrievedImage.Body)
-		if err != nil {
-			return "", fmt.Errorf("failed to read fetched content: %v", err)
-		}
-		defer retrievedImage.Body.Close()
+	// Resolved once here, since images converted concurrently by ConvertDir() only read it
+	graphicsDetected = graphics == "auto"
+	if graphicsDetected {
+		graphics = detectGraphics()
+	}
+	colorMode = flags.ColorMode
+	colorDistance = flags.ColorDistance
+	urlTimeout = flags.URLTimeout
+	if urlTimeout <= 0 {
+		urlTimeout = defaultURLTimeout
+	}
+	userAgent = flags.UserAgent
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+	maxDownloadSize = flags.MaxDownloadSize
+	if maxDownloadSize <= 0 {
+		maxDownloadSize = defaultMaxDownloadSize
+	}
 
-		urlImgName = path.Base(filePath)
-		fmt.Printf("                          \r") // To erase "Fetching image from url..." text from terminal
+	noAutoOrient = flags.NoAutoOrient
+	rotate = flags.Rotate
+	tileRows = flags.TileRows
+	if tileRows > 0 && (saveTxtPath != "" || saveJsonPath != "" || saveImagePath != "" || saveHtmlPath != "" || saveSvgPath != "") {
+		return fmt.Errorf("files can't be saved while converting in tiles")
+	}
+	if tileRows > 0 && borderChars != nil {
+		return fmt.Errorf("borders can't be drawn while converting in tiles")
+	}
+	rotateBgColor = flags.RotateBackgroundColor
+	trim = flags.Trim
+	trimTolerance = flags.TrimTolerance
+	blur = flags.Blur
+	sharpen = flags.Sharpen
+	crop = image.Rectangle{}
+	if flags.Crop != nil {
+		if len(flags.Crop) != 4 || flags.Crop[2] < 1 || flags.Crop[3] < 1 {
+			return fmt.Errorf("crop needs x, y, width and height, with width and height greater than 0")
+		}
+		crop = image.Rect(flags.Crop[0], flags.Crop[1], flags.Crop[0]+flags.Crop[2], flags.Crop[1]+flags.Crop[3])
+	}
 
-	} else {
+	progress = flags.Progress
+	maxConcurrency = flags.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = runtime.NumCPU()
+	}
+	pixelConcurrency = maxConcurrency
+	batchWorkers = flags.BatchWorkers
+	if batchWorkers <= 0 {
+		batchWorkers = maxConcurrency
+	}
 
-		localFile, err = os.Open(filePath)
-		if err != nil {
-			return "", fmt.Errorf("unable to open file: %v", err)
-		}
-		defer localFile.Close()
+	if customMap != "" && utf8.RuneCountInString(customMap) < 2 {
+		return fmt.Errorf("custom map needs at least 2 characters")
+	}
 
+	// Only one kind of character set can be used, so overridden ones are turned off
+	if braille {
+		octant = false
+	}
+	if braille || octant {
+		sextant = false
+	}
+	if braille || octant || sextant {
+		quadrant = false
+	}
+	if braille || octant || sextant || quadrant {
+		halfBlock = false
+	}
+	if braille || octant || sextant || quadrant || halfBlock {
+		bgBlock = false
+	}
+	if braille || octant || sextant || quadrant || halfBlock || bgBlock {
+		edgeMode = ""
 	}
 
 	// If path to font file is provided, use it
 	if fontPath != "" {
 		fontFile, err := ioutil.ReadFile(fontPath)
 		if err != nil {
-			return "", fmt.Errorf("unable to open font file: %v", err)
+			return fmt.Errorf("unable to open font file: %w", err)
 		}
 
 		// tempFont is globally declared in aic_package/create_ascii_image.go
 		if tempFont, err = truetype.Parse(fontFile); err != nil {
-			return "", fmt.Errorf("unable to parse font file: %v", err)
+			return fmt.Errorf("unable to parse font file: %w", err)
 		}
 	} else if braille {
 		tempFont, _ = truetype.Parse(embeddedDejaVuObliqueFont)
 	}
 
-	if path.Ext(filePath) == ".gif" {
-		return "", pathIsGif(filePath, urlImgName, pathIsURl, urlImgBytes, localFile)
+	coverageMatcher = nil
+	if flags.Coverage {
+		coverageFont := hackRegularFont
+		if fontPath != "" {
+			coverageFont = tempFont
+		}
+
+		matcher, err := imgManip.NewCoverageMatcher(coverageFont, complex, customMap)
+		if err != nil {
+			return err
+		}
+		matcher.Invert = negative != invert != reverseMap
+		coverageMatcher = matcher
+	}
+
+	return nil
+}
+
+// Decodes the image or gif from r and writes its ascii art to w, where imagePath and urlImgName are used for naming saved files
+func convertReader(ctx context.Context, w io.Writer, r io.Reader, imagePath, urlImgName string, isGif bool) error {
+	if isGif {
+		// Gifs are always displayed as ascii art, so a detected protocol is ignored for them
+		if graphics != "" && !graphicsDetected {
+			return fmt.Errorf("graphics output isn't supported for gifs")
+		}
+		return pathIsGif(ctx, w, imagePath, urlImgName, r)
 	} else {
-		return pathIsImage(filePath, urlImgName, pathIsURl, urlImgBytes, localFile)
+		return pathIsImage(ctx, w, imagePath, urlImgName, r)
 	}
 }
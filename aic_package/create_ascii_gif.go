@@ -108,6 +108,13 @@ func createGifFrameToSave(asciiArt [][]imgManip.AsciiChar, img image.Image, colo
 
 		for _, char := range line {
 
+			// Half block and background block characters are drawn as two rectangles to fill the whole character space
+			if colorBlocks() {
+				drawHalfBlock(dc, char, xImgPointer, yImgPointer, xIter, yIter)
+				xImgPointer += xIter
+				continue
+			}
+
 			if colored {
 				// dc.SetColor() sets color for EACH character before printing it
 				r := uint8(char.RgbValue[0])
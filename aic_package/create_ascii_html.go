@@ -0,0 +1,112 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aic_package
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"strings"
+
+	imgManip "github.com/TheZoraiz/ascii-image-converter/image_manipulation"
+)
+
+// Saves ascii art as an html page with the name <image-name>-ascii-art.html in saveHtmlPath
+func createHtmlToSave(asciiArt [][]imgManip.AsciiChar, colored bool, saveHtmlPath, imagePath, urlImgName string) error {
+
+	htmlName, err := createSaveFileName(imagePath, urlImgName, "-ascii-art.html")
+	if err != nil {
+		return err
+	}
+
+	fullPathName, err := getFullSavePath(htmlName, saveHtmlPath)
+	if err != nil {
+		return err
+	}
+
+	page := "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>" +
+		html.EscapeString(htmlName) + "</title>\n</head>\n<body>\n" +
+		renderHtml(asciiArt, colored) +
+		"\n</body>\n</html>\n"
+
+	return ioutil.WriteFile(fullPathName, []byte(page), 0666)
+}
+
+/*
+Returns ascii art inside a <pre> element with the save background color. Each run of consecutive characters
+with the same color is wrapped in a single <span>, which keeps the size of html output manageable. The font
+always falls back to monospace so characters stay aligned the same way they do in the terminal.
+*/
+func renderHtml(asciiArt [][]imgManip.AsciiChar, colored bool) string {
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "<pre style=\"background-color:%v;font-family:%v, monospace;line-height:1;letter-spacing:0;font-variant-ligatures:none;padding:5px;display:inline-block\">",
+		htmlColor([3]uint32{uint32(saveBgColor[0]), uint32(saveBgColor[1]), uint32(saveBgColor[2])}),
+		html.EscapeString(htmlFont),
+	)
+
+	for i, line := range asciiArt {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		currentStyle := ""
+		var run strings.Builder
+
+		for _, char := range line {
+			style := htmlCharStyle(char, colored)
+
+			if style != currentStyle && run.Len() > 0 {
+				sb.WriteString("<span style=\"" + currentStyle + "\">" + html.EscapeString(run.String()) + "</span>")
+				run.Reset()
+			}
+
+			currentStyle = style
+			run.WriteString(char.Simple)
+		}
+
+		if run.Len() > 0 {
+			sb.WriteString("<span style=\"" + currentStyle + "\">" + html.EscapeString(run.String()) + "</span>")
+		}
+	}
+
+	sb.WriteString("</pre>")
+
+	return sb.String()
+}
+
+// Returns the inline css for a character, following the same coloring rules as saved images
+func htmlCharStyle(char imgManip.AsciiChar, colored bool) string {
+	if colorBlocks() {
+		return "color:" + htmlColor(char.RgbValue) + ";background-color:" + htmlColor(char.BgRgbValue)
+	}
+
+	charColor := [3]uint32{uint32(fontColor[0]), uint32(fontColor[1]), uint32(fontColor[2])}
+	if colored {
+		charColor = char.RgbValue
+	}
+
+	if colorBg {
+		return "background-color:" + htmlColor(charColor)
+	}
+	return "color:" + htmlColor(charColor)
+}
+
+func htmlColor(rgb [3]uint32) string {
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
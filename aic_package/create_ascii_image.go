@@ -35,9 +35,13 @@ var embeddedDejaVuObliqueFont []byte
 
 var tempFont *truetype.Font
 
+// Kept separately from tempFont, which is replaced by other fonts, so coverage of its glyphs is only measured once
+var hackRegularFont *truetype.Font
+
 // Load embedded font
 func init() {
-	tempFont, _ = truetype.Parse(embeddedHackRegularFont)
+	hackRegularFont, _ = truetype.Parse(embeddedHackRegularFont)
+	tempFont = hackRegularFont
 }
 
 /*
@@ -100,6 +104,13 @@ func createImageToSave(asciiArt [][]imgManip.AsciiChar, colored bool, saveImageP
 
 		for _, char := range line {
 
+			// Half block and background block characters are drawn as two rectangles to fill the whole character space
+			if colorBlocks() {
+				drawHalfBlock(dc, char, xImgPointer, yImgPointer, constant, constant*2)
+				xImgPointer += float64(constant)
+				continue
+			}
+
 			if colored {
 				// dc.SetColor() sets color for EACH character before printing it
 				r := uint8(char.RgbValue[0])
@@ -140,3 +151,14 @@ func createImageToSave(asciiArt [][]imgManip.AsciiChar, colored bool, saveImageP
 
 	return dc.SavePNG(fullPathName)
 }
+
+// Draws upper and lower halves of a half block character with their respective colors
+func drawHalfBlock(dc *gg.Context, char imgManip.AsciiChar, x, y, charWidth, charHeight float64) {
+	dc.SetColor(color.RGBA{uint8(char.RgbValue[0]), uint8(char.RgbValue[1]), uint8(char.RgbValue[2]), 255})
+	dc.DrawRectangle(x, y, charWidth, charHeight/2)
+	dc.Fill()
+
+	dc.SetColor(color.RGBA{uint8(char.BgRgbValue[0]), uint8(char.BgRgbValue[1]), uint8(char.BgRgbValue[2]), 255})
+	dc.DrawRectangle(x, y+charHeight/2, charWidth, charHeight/2)
+	dc.Fill()
+}
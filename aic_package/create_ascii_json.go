@@ -0,0 +1,63 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aic_package
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	imgManip "github.com/TheZoraiz/ascii-image-converter/image_manipulation"
+)
+
+// Layout of saved .json files
+type jsonGrid struct {
+	Width  int               `json:"width"`
+	Height int               `json:"height"`
+	Cells  [][]imgManip.Cell `json:"cells"`
+}
+
+/*
+Saves the character and pixel values of each cell of the ascii art as a .json file, so results can be used by other
+tools or inspected to see why cells got their characters. Cells are stored row by row.
+*/
+func createJsonToSave(imgSet [][]imgManip.AsciiPixel, asciiArt [][]imgManip.AsciiChar, saveJsonPath, imagePath, urlImgName string) error {
+
+	jsonName, err := createSaveFileName(imagePath, urlImgName, "-ascii-art.json")
+	if err != nil {
+		return err
+	}
+
+	fullPathName, err := getFullSavePath(jsonName, saveJsonPath)
+	if err != nil {
+		return err
+	}
+
+	grid := jsonGrid{
+		Height: len(asciiArt),
+		Cells:  imgManip.GridCells(imgSet, asciiArt),
+	}
+	if len(asciiArt) > 0 {
+		grid.Width = len(asciiArt[0])
+	}
+
+	data, err := json.Marshal(grid)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fullPathName, data, 0666)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aic_package
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"strings"
+
+	imgManip "github.com/TheZoraiz/ascii-image-converter/image_manipulation"
+)
+
+// Saves ascii art as an svg file with the name <image-name>-ascii-art.svg in saveSvgPath
+func createSvgToSave(asciiArt [][]imgManip.AsciiChar, colored bool, saveSvgPath, imagePath, urlImgName string) error {
+
+	svgName, err := createSaveFileName(imagePath, urlImgName, "-ascii-art.svg")
+	if err != nil {
+		return err
+	}
+
+	fullPathName, err := getFullSavePath(svgName, saveSvgPath)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fullPathName, []byte(renderSvg(asciiArt, colored)), 0666)
+}
+
+/*
+Returns ascii art as an svg document where each character occupies a cell of Flags.SvgCellSize on a grid, so the
+viewBox follows the ascii art dimensions. Consecutive characters with the same color are grouped into a single
+<text> element, whose textLength keeps them aligned to the grid regardless of font.
+*/
+func renderSvg(asciiArt [][]imgManip.AsciiChar, colored bool) string {
+
+	cellWidth := float64(svgCellSize[0])
+	cellHeight := float64(svgCellSize[1])
+
+	svgWidth := cellWidth * float64(len(asciiArt[0]))
+	svgHeight := cellHeight * float64(len(asciiArt))
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 %v %v\" width=\"%v\" height=\"%v\" font-family=\"%v, monospace\" font-size=\"%v\">\n",
+		svgWidth, svgHeight, svgWidth, svgHeight, html.EscapeString(svgFont), cellHeight*0.8)
+
+	fmt.Fprintf(&sb, "<rect width=\"100%%\" height=\"100%%\" fill=\"%v\"/>\n",
+		htmlColor([3]uint32{uint32(saveBgColor[0]), uint32(saveBgColor[1]), uint32(saveBgColor[2])}))
+
+	for row, line := range asciiArt {
+
+		// Baseline is raised from the bottom of the cell to leave room for descenders
+		y := cellHeight*float64(row+1) - cellHeight*0.2
+
+		for start := 0; start < len(line); {
+
+			fill := svgCharColor(line[start], colored)
+
+			end := start + 1
+			for end < len(line) && svgCharColor(line[end], colored) == fill {
+				end++
+			}
+
+			if colorBlocks() {
+				// Half block and background block characters are drawn as two rectangles to fill the whole cell, like in saved png files
+				for col := start; col < end; col++ {
+					x := cellWidth * float64(col)
+					fmt.Fprintf(&sb, "<rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"%v\"/>", x, cellHeight*float64(row), cellWidth, cellHeight/2, htmlColor(line[col].RgbValue))
+					fmt.Fprintf(&sb, "<rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"%v\"/>\n", x, cellHeight*float64(row)+cellHeight/2, cellWidth, cellHeight/2, htmlColor(line[col].BgRgbValue))
+				}
+				start = end
+				continue
+			}
+
+			var text strings.Builder
+			for _, char := range line[start:end] {
+				text.WriteString(char.Simple)
+			}
+
+			// Runs of empty spaces don't need to be drawn at all
+			if strings.TrimSpace(text.String()) != "" {
+				fmt.Fprintf(&sb, "<text x=\"%v\" y=\"%v\" fill=\"%v\" textLength=\"%v\" lengthAdjust=\"spacingAndGlyphs\" xml:space=\"preserve\">%v</text>\n",
+					cellWidth*float64(start), y, fill, cellWidth*float64(end-start), html.EscapeString(text.String()))
+			}
+
+			start = end
+		}
+	}
+
+	sb.WriteString("</svg>\n")
+
+	return sb.String()
+}
+
+// Returns the fill color of a character, following the same coloring rules as saved images
+func svgCharColor(char imgManip.AsciiChar, colored bool) string {
+	if colored {
+		return htmlColor(char.RgbValue)
+	}
+	return htmlColor([3]uint32{uint32(fontColor[0]), uint32(fontColor[1]), uint32(fontColor[2])})
+}
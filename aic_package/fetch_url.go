@@ -0,0 +1,84 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aic_package
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Used when Flags.URLTimeout, Flags.UserAgent or Flags.MaxDownloadSize aren't set
+const (
+	defaultURLTimeout      = 30 * time.Second
+	defaultUserAgent       = "ascii-image-converter"
+	defaultMaxDownloadSize = 50 << 20
+)
+
+/*
+Downloads the file at url and returns its contents. An error is returned if the server doesn't respond with
+200 OK, if the response isn't an image (application/octet-stream is allowed since some servers use it for any
+binary file) or if it's larger than Flags.MaxDownloadSize.
+*/
+func fetchURL(ctx context.Context, url string) ([]byte, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, urlTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't fetch content: %w", err)
+	}
+	request.Header.Set("User-Agent", userAgent)
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("can't fetch content: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("can't fetch content: server responded with %v", response.Status)
+	}
+
+	if contentType := response.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (!strings.HasPrefix(mediaType, "image/") && mediaType != "application/octet-stream") {
+			return nil, fmt.Errorf("can't fetch content: expected an image but got %v", contentType)
+		}
+	}
+
+	if response.ContentLength > maxDownloadSize {
+		return nil, fmt.Errorf("can't fetch content: file is larger than %v bytes", maxDownloadSize)
+	}
+
+	// Content-Length may be missing or wrong, so one extra byte is read to check whether the limit is exceeded
+	content, err := ioutil.ReadAll(io.LimitReader(response.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fetched content: %w", err)
+	}
+	if int64(len(content)) > maxDownloadSize {
+		return nil, fmt.Errorf("can't fetch content: file is larger than %v bytes", maxDownloadSize)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,286 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aic_package
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/TheZoraiz/ascii-image-converter/aic_package/winsize"
+	imgManip "github.com/TheZoraiz/ascii-image-converter/image_manipulation"
+	"github.com/disintegration/imaging"
+)
+
+// Maximum size of base64 payload in each chunk of a kitty graphics escape sequence
+const kittyChunkSize = 4096
+
+// Used when the terminal doesn't report the pixel size of its characters
+const (
+	defaultCellWidth  = 10
+	defaultCellHeight = 20
+)
+
+/*
+Instead of ascii art, this function returns the passed image as an escape sequence for the graphics protocol
+set in Flags.Graphics. The image is resized to cover the same number of characters in the terminal as its
+ascii art would, so passed dimensions and the full flag work the same way.
+*/
+func graphicsOutput(img image.Image) (string, error) {
+
+	opts := pixelOptions()
+
+	img, err := imgManip.TransformImage(img, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// Each pixel of this image corresponds to a single character of non-braille ascii art, so block characters
+	// are left out of the sizing options to keep them from resizing it to several pixels per character
+	sizeOpts := opts
+	sizeOpts.HalfBlock, sizeOpts.Quadrant, sizeOpts.Sextant, sizeOpts.Octant = false, false, false, false
+
+	smallImg, err := imgManip.ResizeImage(img, dimensions, width, height, full, false, sizeOpts)
+	if err != nil {
+		return "", err
+	}
+	columns := smallImg.Bounds().Dx()
+	rows := smallImg.Bounds().Dy()
+
+	cellWidth, cellHeight, err := winsize.GetCellSize()
+	if err != nil {
+		cellWidth, cellHeight = defaultCellWidth, defaultCellHeight
+	}
+
+	filter, err := imgManip.ResizeFilter(opts)
+	if err != nil {
+		return "", err
+	}
+
+	pixelImg := imaging.Resize(img, columns*cellWidth, rows*cellHeight, filter)
+	if flipX {
+		pixelImg = imaging.FlipH(pixelImg)
+	}
+	if flipY {
+		pixelImg = imaging.FlipV(pixelImg)
+	}
+
+	switch graphics {
+	case "sixel":
+		if !sixelSupported() {
+			return "", fmt.Errorf("terminal doesn't advertise sixel support")
+		}
+		return encodeSixel(pixelImg), nil
+
+	case "kitty":
+		return encodeKitty(pixelImg, columns, rows)
+
+	case "iterm":
+		return encodeITerm(pixelImg, columns, rows)
+
+	default:
+		return "", fmt.Errorf("unknown graphics protocol %q", graphics)
+	}
+}
+
+/*
+Returns the richest graphics protocol the terminal seems to support, or an empty string if none is found.
+Kitty and iTerm2 are recognized from their environment variables, while sixel support is queried from the
+terminal itself.
+*/
+func detectGraphics() string {
+	termProgram := os.Getenv("TERM_PROGRAM")
+
+	if termProgram == "iTerm.app" {
+		return "iterm"
+	}
+	if os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("TERM") == "xterm-kitty" || termProgram == "WezTerm" {
+		return "kitty"
+	}
+	if sixelSupported() {
+		return "sixel"
+	}
+
+	return ""
+}
+
+// Checks whether the terminal reports sixel graphics (attribute 4) in its primary device attributes
+func sixelSupported() bool {
+	response, err := winsize.QueryTerminal("\x1b[c", 'c')
+	if err != nil {
+		return false
+	}
+
+	response = strings.TrimPrefix(response, "\x1b[?")
+	response = strings.TrimSuffix(response, "c")
+
+	for _, attribute := range strings.Split(response, ";") {
+		if attribute == "4" {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+Encodes passed image as a sixel escape sequence. The image is first reduced to a 256 color palette, after which
+each band of 6 rows is written once per color present in it, with repeated sixels being run-length encoded.
+*/
+func encodeSixel(img image.Image) string {
+
+	b := img.Bounds()
+	imgWidth := b.Dx()
+	imgHeight := b.Dy()
+
+	palettedImg := image.NewPaletted(image.Rect(0, 0, imgWidth, imgHeight), palette.Plan9)
+	draw.FloydSteinberg.Draw(palettedImg, palettedImg.Bounds(), img, b.Min)
+
+	var sb strings.Builder
+
+	// Start sixel sequence with 1:1 pixel aspect ratio and image dimensions
+	sb.WriteString("\x1bPq")
+	sb.WriteString("\"1;1;" + strconv.Itoa(imgWidth) + ";" + strconv.Itoa(imgHeight))
+
+	// Define color registers, whose RGB values range from 0 to 100 in sixel
+	for i, c := range palettedImg.Palette {
+		r, g, b, _ := c.RGBA()
+		sb.WriteString("#" + strconv.Itoa(i) + ";2;" +
+			strconv.Itoa(int(r*100/0xffff)) + ";" +
+			strconv.Itoa(int(g*100/0xffff)) + ";" +
+			strconv.Itoa(int(b*100/0xffff)))
+	}
+
+	bandBits := make([][]byte, len(palettedImg.Palette))
+
+	for bandY := 0; bandY < imgHeight; bandY += 6 {
+
+		var bandColors []int
+
+		for y := bandY; y < bandY+6 && y < imgHeight; y++ {
+			for x := 0; x < imgWidth; x++ {
+				index := palettedImg.ColorIndexAt(x, y)
+
+				if bandBits[index] == nil {
+					bandBits[index] = make([]byte, imgWidth)
+					bandColors = append(bandColors, int(index))
+				}
+				bandBits[index][x] |= 1 << uint(y-bandY)
+			}
+		}
+
+		for i, index := range bandColors {
+			// Return to start of band before drawing each color after the first
+			if i > 0 {
+				sb.WriteByte('$')
+			}
+
+			sb.WriteString("#" + strconv.Itoa(index))
+			writeSixelRuns(&sb, bandBits[index])
+
+			bandBits[index] = nil
+		}
+
+		sb.WriteByte('-')
+	}
+
+	sb.WriteString("\x1b\\")
+
+	return sb.String()
+}
+
+// Writes a row of sixel bits, replacing runs of more than 3 identical sixels with a repeat introducer
+func writeSixelRuns(sb *strings.Builder, bits []byte) {
+	for x := 0; x < len(bits); {
+		run := 1
+		for x+run < len(bits) && bits[x+run] == bits[x] {
+			run++
+		}
+
+		char := string(rune(63 + bits[x]))
+		if run > 3 {
+			sb.WriteString("!" + strconv.Itoa(run) + char)
+		} else {
+			sb.WriteString(strings.Repeat(char, run))
+		}
+
+		x += run
+	}
+}
+
+/*
+Encodes passed image as PNG and returns it as kitty graphics protocol escape sequences, displayed over the passed
+number of columns and rows. The base64 payload is split into chunks of at most 4096 bytes, where every chunk
+except the last one is marked with m=1.
+*/
+func encodeKitty(img image.Image, columns, rows int) (string, error) {
+
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, img); err != nil {
+		return "", err
+	}
+	payload := base64.StdEncoding.EncodeToString(pngBuf.Bytes())
+
+	var sb strings.Builder
+
+	for start := 0; start < len(payload); start += kittyChunkSize {
+		end := start + kittyChunkSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+
+		more := "1"
+		if end == len(payload) {
+			more = "0"
+		}
+
+		sb.WriteString("\x1b_G")
+
+		// Control data other than m is only read from the first chunk
+		if start == 0 {
+			sb.WriteString("a=T,f=100,c=" + strconv.Itoa(columns) + ",r=" + strconv.Itoa(rows) + ",")
+		}
+
+		sb.WriteString("m=" + more + ";" + payload[start:end] + "\x1b\\")
+	}
+
+	return sb.String(), nil
+}
+
+// Encodes passed image as PNG and returns it as an iTerm2 inline image escape sequence, displayed over the passed
+// number of columns and rows
+func encodeITerm(img image.Image, columns, rows int) (string, error) {
+
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, img); err != nil {
+		return "", err
+	}
+
+	return "\x1b]1337;File=inline=1" +
+		";size=" + strconv.Itoa(pngBuf.Len()) +
+		";width=" + strconv.Itoa(columns) +
+		";height=" + strconv.Itoa(rows) +
+		";preserveAspectRatio=0:" +
+		base64.StdEncoding.EncodeToString(pngBuf.Bytes()) + "\a", nil
+}
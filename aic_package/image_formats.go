@@ -0,0 +1,60 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aic_package
+
+// Length of a RIFF header followed by the flags of a VP8X chunk, which is enough to tell whether a webp image is animated
+const webpHeaderSize = 21
+
+/*
+Reports whether header belongs to an animated webp image. Animated webp images use the extended format, where
+the RIFF header is followed by a VP8X chunk whose flags have the animation bit set.
+*/
+func isAnimatedWebp(header []byte) bool {
+	if len(header) < webpHeaderSize {
+		return false
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WEBP" || string(header[12:16]) != "VP8X" {
+		return false
+	}
+
+	const animationBit = 1 << 1
+	return header[20]&animationBit != 0
+}
+
+// Brands of the ftyp box at the start of HEIF files, which AVIF and HEIC are both based on
+var heifBrands = map[string]string{
+	"avif": "avif",
+	"avis": "avif",
+	"heic": "heic",
+	"heix": "heic",
+	"hevc": "heic",
+	"hevx": "heic",
+	"mif1": "heif",
+	"msf1": "heif",
+}
+
+/*
+Returns "avif", "heic" or "heif" if header belongs to one of those formats, or an empty string otherwise.
+There are no decoders for these formats in the standard library or golang.org/x/image, so this is used to
+explain decoding errors. Library users can still convert them by registering a decoder with a blank import.
+*/
+func heifFormat(header []byte) string {
+	if len(header) < 12 || string(header[4:8]) != "ftyp" {
+		return ""
+	}
+	return heifBrands[string(header[8:12])]
+}
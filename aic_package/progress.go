@@ -0,0 +1,52 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aic_package
+
+import (
+	"sync"
+	"time"
+)
+
+// Statistics of a single converted image or gif frame, passed to Flags.Progress
+type Stats struct {
+	// Path or url of the image or gif, as passed to Convert(), or "-" for stdin
+	Path string
+
+	// Index of the gif frame, along with the number of frames in the gif. These are 0 and 1 for images
+	Frame, Frames int
+
+	// Time taken to convert the image or frame into ascii art, not including decoding or writing it
+	Duration time.Duration
+
+	// Number of characters in each row and column of the ascii art, and in total
+	Width, Height, Chars int
+}
+
+// Calls to progress are serialized, since gif frames and directory images are converted concurrently
+var progressMutex sync.Mutex
+
+// Passes stats to Flags.Progress if it's set
+func reportProgress(stats Stats) {
+	if progress == nil {
+		return
+	}
+
+	progressMutex.Lock()
+	defer progressMutex.Unlock()
+
+	progress(stats)
+}
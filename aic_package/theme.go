@@ -0,0 +1,92 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aic_package
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/TheZoraiz/ascii-image-converter/aic_package/winsize"
+)
+
+// Reports whether passed theme is "light", or is "auto" and the terminal seems to have a light background
+func lightTheme(theme string) bool {
+	if theme == "auto" {
+		theme = detectTheme()
+	}
+	return theme == "light"
+}
+
+/*
+Returns "light" or "dark" for the background of the terminal. It's queried through an OSC 11 escape sequence first,
+and the COLORFGBG environment variable that some terminals set is used if the terminal doesn't respond. Falls back
+to "dark" if neither is available, since the theme can be set explicitly where detection isn't possible.
+*/
+func detectTheme() string {
+	// Terminals that don't end their response with BEL are still parsed once the query times out
+	response, _ := winsize.QueryTerminal("\x1b]11;?\a", '\a')
+	if luminance, ok := parseOscColor(response); ok {
+		if luminance > 0.5 {
+			return "light"
+		}
+		return "dark"
+	}
+
+	// COLORFGBG holds the ANSI color indices of foreground and background, such as "15;0"
+	if fgbg := os.Getenv("COLORFGBG"); fgbg != "" {
+		fields := strings.Split(fgbg, ";")
+		if bg, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			if bg == 7 || bg == 15 {
+				return "light"
+			}
+			return "dark"
+		}
+	}
+
+	return "dark"
+}
+
+// Returns the relative luminance, from 0 to 1, of the rgb:RRRR/GGGG/BBBB color in an OSC color query response,
+// where each component has 1 to 4 hex digits
+func parseOscColor(response string) (float64, bool) {
+	start := strings.Index(response, "rgb:")
+	if start == -1 {
+		return 0, false
+	}
+
+	body := strings.TrimRight(response[start+len("rgb:"):], "\a\x1b\\")
+	components := strings.Split(body, "/")
+	if len(components) != 3 {
+		return 0, false
+	}
+
+	var rgb [3]float64
+	for i, component := range components {
+		if len(component) == 0 || len(component) > 4 {
+			return 0, false
+		}
+
+		value, err := strconv.ParseUint(component, 16, 16)
+		if err != nil {
+			return 0, false
+		}
+		rgb[i] = float64(value) / float64(uint64(1)<<(4*len(component))-1)
+	}
+
+	return 0.2126*rgb[0] + 0.7152*rgb[1] + 0.0722*rgb[2], true
+}
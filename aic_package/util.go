@@ -18,6 +18,8 @@ package aic_package
 
 import (
 	"fmt"
+	"image/color"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -25,6 +27,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/TheZoraiz/ascii-image-converter/aic_package/winsize"
 	imgManip "github.com/TheZoraiz/ascii-image-converter/image_manipulation"
 )
 
@@ -79,27 +82,255 @@ func flattenAscii(asciiSet [][]imgManip.AsciiChar, colored, toSaveTxt bool) []st
 	var ascii []string
 
 	for _, line := range asciiSet {
-		var tempAscii string
+		ascii = append(ascii, flattenLine(line, colored, toSaveTxt))
+	}
 
-		for _, char := range line {
-			if toSaveTxt {
-				tempAscii += char.Simple
-				continue
-			}
+	return ascii
+}
 
-			if colored {
-				tempAscii += char.OriginalColor
-			} else if fontColor != [3]int{255, 255, 255} {
-				tempAscii += char.SetColor
-			} else {
-				tempAscii += char.Simple
+// Writes each line of ascii art to w as soon as it's flattened, separated by newlines the same way as
+// joining the result of flattenAscii() would
+func writeAscii(w io.Writer, asciiSet [][]imgManip.AsciiChar, colored bool) error {
+	var indent string
+	if len(asciiSet) > 0 {
+		indent = strings.Repeat(" ", centerColumns(len(asciiSet[0])))
+	}
+
+	for i, line := range asciiSet {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
 			}
 		}
 
-		ascii = append(ascii, tempAscii)
+		if _, err := io.WriteString(w, indent+flattenLine(line, colored, false)); err != nil {
+			return err
+		}
 	}
 
-	return ascii
+	return nil
+}
+
+/*
+Returns the number of spaces that ascii art of passed width is indented by to center it in the terminal with
+Flags.Center. This is 0 when stdout isn't a terminal, since there's nothing to center it in.
+*/
+func centerColumns(artWidth int) int {
+	if !center {
+		return 0
+	}
+	terminalWidth, _, ok := centerTerminalSize()
+	if !ok || terminalWidth <= artWidth {
+		return 0
+	}
+	return (terminalWidth - artWidth) / 2
+}
+
+// Returns the number of blank lines printed before ascii art of passed height with Flags.CenterVertical, the same way as centerColumns()
+func centerRows(artHeight int) int {
+	if !centerVertical {
+		return 0
+	}
+	_, terminalHeight, ok := centerTerminalSize()
+	if !ok || terminalHeight <= artHeight {
+		return 0
+	}
+	return (terminalHeight - artHeight) / 2
+}
+
+// Returns the size of the terminal ascii art is centered in, preferring Flags.TerminalSize, and whether stdout is a terminal at all
+func centerTerminalSize() (int, int, bool) {
+	fileInfo, err := os.Stdout.Stat()
+	if err != nil || fileInfo.Mode()&os.ModeCharDevice == 0 {
+		return 0, 0, false
+	}
+
+	if terminalSize != [2]int{} {
+		return terminalSize[0], terminalSize[1], true
+	}
+
+	terminalWidth, terminalHeight, err := winsize.GetTerminalSize()
+	if err != nil {
+		return 0, 0, false
+	}
+	return terminalWidth, terminalHeight, true
+}
+
+func flattenLine(line []imgManip.AsciiChar, colored, toSaveTxt bool) string {
+	var sb strings.Builder
+
+	for _, char := range line {
+		if toSaveTxt {
+			sb.WriteString(char.Simple)
+			continue
+		}
+
+		if colored {
+			sb.WriteString(char.OriginalColor)
+		} else if fontColor != [3]int{255, 255, 255} {
+			sb.WriteString(char.SetColor)
+		} else {
+			sb.WriteString(char.Simple)
+		}
+	}
+
+	return sb.String()
+}
+
+// Whether characters are drawn with colors alone, so ascii art is colored even if Flags.Colored and Flags.Grayscale aren't set
+func colorBlocks() bool {
+	return halfBlock || bgBlock
+}
+
+// Converts passed imgSet to characters of the character set chosen in flags
+func asciiChars(imgSet [][]imgManip.AsciiPixel) [][]imgManip.AsciiChar {
+	if braille {
+		return imgManip.ConvertToBrailleCharsWithOptions(imgSet, negative, colored, colorBg, fontColor, threshold, charOptions())
+	} else if octant {
+		return imgManip.ConvertToOctantChars(imgSet, negative, colored, colorBg, fontColor, threshold, charOptions())
+	} else if sextant {
+		return imgManip.ConvertToSextantChars(imgSet, negative, colored, colorBg, fontColor, threshold, charOptions())
+	} else if quadrant {
+		return imgManip.ConvertToQuadrantChars(imgSet, negative, colored, colorBg, fontColor, threshold, charOptions())
+	} else if halfBlock {
+		return imgManip.ConvertToHalfBlockChars(imgSet, negative, colored, charOptions())
+	} else if bgBlock {
+		return imgManip.ConvertToBgBlockChars(imgSet, negative, colored, charOptions())
+	} else if edgeMode != "" {
+		return imgManip.ConvertToEdgeChars(imgSet, negative, colored, colorBg, fontColor, charOptions())
+	}
+	return imgManip.ConvertToAsciiCharsWithOptions(imgSet, negative, colored, complex, colorBg, customMap, fontColor, charOptions())
+}
+
+/*
+Pads asciiSet to Flags.Dimensions when Flags.Fit is "contain", so the image is centered in ascii art of the set size.
+Strips converted with Flags.TileRows are only padded horizontally, since they're written one at a time.
+*/
+func padAscii(asciiSet [][]imgManip.AsciiChar, vertical bool) [][]imgManip.AsciiChar {
+	if fit != "contain" || len(dimensions) != 2 || full {
+		return asciiSet
+	}
+
+	boxHeight := dimensions[1]
+	if !vertical {
+		boxHeight = len(asciiSet)
+	}
+	return imgManip.PadAsciiChars(asciiSet, dimensions[0], boxHeight, padChar, padColor, charOptions())
+}
+
+// Draws a frame of Flags.Border around asciiSet, if it's set
+func borderAscii(asciiSet [][]imgManip.AsciiChar) [][]imgManip.AsciiChar {
+	if borderChars == nil {
+		return asciiSet
+	}
+	return imgManip.FrameAsciiChars(asciiSet, *borderChars, borderColor, charOptions())
+}
+
+// Collects the pixel-level settings passed to imgManip.ConvertToAsciiPixelsWithOptions()
+func pixelOptions() imgManip.PixelOptions {
+	ditherLevels := 2
+	if !braille && !quadrant && !sextant && !octant {
+		ditherLevels = imgManip.CharacterCount(complex, customMap)
+	}
+
+	opts := imgManip.PixelOptions{
+		Crop:               crop,
+		Trim:               trim,
+		TrimTolerance:      trimTolerance,
+		Blur:               blur,
+		Sharpen:            sharpen,
+		Background:         color.RGBA{uint8(alphaBgColor[0]), uint8(alphaBgColor[1]), uint8(alphaBgColor[2]), 255},
+		AlphaWeighted:      alphaWeighted,
+		Checkerboard:       checkerboard,
+		CheckerSize:        checkerSize,
+		Rotate:             rotate,
+		RotateBackground:   color.RGBA{uint8(rotateBgColor[0]), uint8(rotateBgColor[1]), uint8(rotateBgColor[2]), 255},
+		Dithering:          dithering,
+		DitherMode:         ditherMode,
+		BayerSize:          bayerSize,
+		HalfBlock:          halfBlock,
+		Quadrant:           quadrant,
+		Sextant:            sextant,
+		Octant:             octant,
+		BrailleDots:        brailleDots,
+		DitherLevels:       ditherLevels,
+		Gamma:              gamma,
+		Brightness:         brightness,
+		Contrast:           contrast,
+		Saturation:         saturation,
+		Sepia:              sepia,
+		Posterize:          posterize,
+		Equalize:           equalize,
+		Luminance:          luminance,
+		LuminanceWeights:   luminanceWeights,
+		HighPrecision:      highPrecision,
+		LinearResize:       linearResize,
+		EdgeMode:           edgeMode,
+		EdgeThreshold:      edgeThreshold,
+		EdgeLowThreshold:   edgeLowThreshold,
+		EdgeBlur:           edgeBlur,
+		ResizeFilter:       resizeFilter,
+		FontRatio:          fontRatio,
+		NoAspectCorrection: noAspectCorrection,
+		Fit:                fit,
+		TerminalWidth:      terminalSize[0],
+		TerminalHeight:     terminalSize[1],
+		MaxConcurrency:     pixelConcurrency,
+		Rainbow:            rainbow,
+		Quantize:           quantize,
+		QuantizeMethod:     quantizeMethod,
+		Palette:            colorPalette,
+		PaletteColors:      customPalette,
+		PaletteDistance:    colorDistance,
+	}
+
+	if useGradient {
+		opts.GradientStart = color.RGBA{uint8(gradient[0][0]), uint8(gradient[0][1]), uint8(gradient[0][2]), 255}
+		opts.GradientEnd = color.RGBA{uint8(gradient[1][0]), uint8(gradient[1][1]), uint8(gradient[1][2]), 255}
+	}
+
+	if checkerboard {
+		opts.CheckerColors[0] = color.RGBA{uint8(checkerColors[0][0]), uint8(checkerColors[0][1]), uint8(checkerColors[0][2]), 255}
+		opts.CheckerColors[1] = color.RGBA{uint8(checkerColors[1][0]), uint8(checkerColors[1][1]), uint8(checkerColors[1][2]), 255}
+	}
+
+	return opts
+}
+
+// Returns the number of goroutines each of passed number of concurrent conversions can use for its pixels,
+// so they don't exceed Flags.MaxConcurrency together
+func splitConcurrency(conversions int) int {
+	if conversions < 1 || conversions >= maxConcurrency {
+		return 1
+	}
+	return maxConcurrency / conversions
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Collects the character-level settings passed to imgManip's character conversion functions
+func charOptions() imgManip.CharOptions {
+	opts := imgManip.CharOptions{
+		ColorMode:       colorMode,
+		ColorDistance:   colorDistance,
+		Invert:          invert,
+		ReverseMap:      reverseMap,
+		AlphaThreshold:  alphaThreshold,
+		TransparentChar: transparentChar,
+		DualColor:       dualColor,
+		BrailleDots:     brailleDots,
+	}
+
+	// Left unset when nil, since a nil *CoverageMatcher wouldn't be a nil CharMatcher
+	if coverageMatcher != nil {
+		opts.Matcher = coverageMatcher
+	}
+	return opts
 }
 
 // Returns path with the file name concatenated to it
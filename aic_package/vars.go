@@ -16,6 +16,13 @@ limitations under the License.
 
 package aic_package
 
+import (
+	"image"
+	"time"
+
+	imgManip "github.com/TheZoraiz/ascii-image-converter/image_manipulation"
+)
+
 type Flags struct {
 	// Set dimensions of ascii art. Accepts a slice of 2 integers
 	// e.g. []int{60,30}.
@@ -36,15 +43,68 @@ type Flags struct {
 	// Path to save ascii art .txt file
 	SaveTxtPath string
 
-	// Path to save ascii art .png file
+	// Path to save a .json file with each character of the ascii art along with the depth, grayscale,
+	// RGB and alpha values of the pixel it was mapped from
+	SaveJsonPath string
+
+	// Path to save ascii art .png file. Each character is drawn with the embedded Hack-Regular
+	// font (or Flags.FontFilePath) in a 14x28 pixel cell, colored the same way as in the terminal,
+	// over Flags.SaveBackgroundColor
 	SaveImagePath string
 
 	// Path to save ascii art .gif file, if gif is passed
 	SaveGifPath string
 
+	// Clear the whole screen before each frame when playing a gif, instead of only moving
+	// the cursor back to the top of the screen
+	GifClear bool
+
+	// Play the gif repeatedly until interrupted, ignoring the gif's own loop count.
+	// This overrides Flags.LoopCount
+	GifLoop bool
+
+	// Number of times the gif is played in the terminal before its last frame is left on screen,
+	// where 0 plays it forever. Negative values use the gif's own loop count
+	LoopCount int
+
+	// Play gif frames at this fixed frame rate instead of using their own delays, for gifs
+	// with broken or zero delays. Frame delays are used when set to 0
+	FPS float64
+
+	// Path to save ascii art .html file, with each character colored the same way
+	// as in saved png files. Flags.SaveBackgroundColor is used as the page's background
+	SaveHtmlPath string
+
+	// Font family used in saved html files. Monospace is always kept as a fallback.
+	// This will be ignored if Flags.SaveHtmlPath is not set
+	HtmlFont string
+
+	// Path to save ascii art .svg file, with each character colored the same way
+	// as in saved png files. Flags.SaveBackgroundColor is used as the background
+	SaveSvgPath string
+
+	// Width and height of each character's cell in saved svg files.
+	// This will be ignored if Flags.SaveSvgPath is not set
+	SvgCellSize [2]int
+
+	// Font family used in saved svg files. Monospace is always kept as a fallback.
+	// This will be ignored if Flags.SaveSvgPath is not set
+	SvgFont string
+
 	// Invert ascii art character mapping as well as colors
 	Negative bool
 
+	// Invert ascii art character mapping while keeping colors, so dark pixels get dense characters.
+	// Useful for terminals with light backgrounds. For braille and quadrant art, this inverts
+	// which dots are filled
+	Invert bool
+
+	// Background of the terminal ascii art is displayed on. Either "dark", "light" or "auto", which
+	// queries the terminal for its background color and falls back to "dark" if it can't be found.
+	// "light" inverts character mapping like Flags.Invert, so art reads correctly on light backgrounds,
+	// which cancels out Flags.Invert if both are set
+	Theme string
+
 	// Keep colors from the original image. This uses the True color codes for
 	// the terminal and will work on saved .png and .gif files as well.
 	// This overrides Flags.Grayscale and Flags.FontColor
@@ -54,22 +114,180 @@ type Flags struct {
 	// on each character's background in the terminal
 	CharBackgroundColor bool
 
-	// Keep grayscale colors from the original image. This uses the True color
-	// codes for the terminal and will work on saved .png and .gif files as well
-	// This overrides Flags.FontColor
+	// If Flags.Colored or Flags.Grayscale is set, color each character's background with a darkened color of its
+	// pixel and the character itself with a lightened one. This overrides Flags.CharBackgroundColor for pixel colors
+	DualColor bool
+
+	// Color each character with the gray level of its pixel instead of its full color, which
+	// gives a shaded look between plain and colored ascii art. Escape codes follow Flags.ColorMode
+	// and this will work on saved .png and .gif files as well. This overrides Flags.FontColor
 	Grayscale bool
 
-	// Pass custom ascii art characters as a string.
-	// e.g. " .-=+#@".
+	// Color each character by its brightness along a gradient between two colors instead of its original color,
+	// for duotone ascii art. Pass the red, green and blue values of the color for the darkest characters followed
+	// by those of the color for the brightest ones, e.g. []int{20,0,80,255,200,0}. This turns on Flags.Colored
+	// and overrides Flags.Grayscale. Unused when nil
+	Gradient []int
+
+	// Color characters with hues cycling across the ascii art instead of their original colors. Either "column"
+	// or "row" for hues varying by position, or "brightness" for hues varying by the brightness of each
+	// character. This turns on Flags.Colored and overrides Flags.Gradient and Flags.Grayscale. Disabled when empty
+	Rainbow string
+
+	// Reduce colors of ascii art to at most this many, from 2 to 256, for a flatter look with fewer color changes
+	// in terminal output and saved files. Applied after Flags.Gradient and Flags.Rainbow. Disabled when set to 0
+	Quantize int
+
+	// Algorithm that generates the palette of Flags.Quantize. Either "median-cut" or "k-means", which is slower
+	// but usually matches the image's colors more closely
+	QuantizeMethod string
+
+	// Map colors of ascii art to the nearest ones of a retro palette, either "cga", "gameboy", "c64", "nes" or
+	// one added with imgManip.RegisterPalette(). Nearest colors are found with Flags.ColorDistance, after
+	// Flags.Quantize is applied. Disabled when empty
+	Palette string
+
+	// Map colors to the nearest of these instead of Flags.Palette. Pass the red, green and blue values of each
+	// color one after another, e.g. []int{0,0,0,255,0,0,255,255,255}. Unused when nil
+	CustomPalette []int
+
+	// Pass custom ascii art characters as a string, ordered from darkest to lightest.
+	// e.g. " .-=+#@". Grayscale values are split across as many levels as there are characters,
+	// and multi-byte characters such as "░▒▓█" are supported. Needs at least 2 characters if set.
 	// This overrides Flags.Complex
 	CustomMap string
 
+	// Reverse the order of the active character set, whether it's Flags.CustomMap or one of the
+	// default ones. Unlike Flags.Invert, this only reorders characters and doesn't affect braille
+	// or block characters
+	ReverseMap bool
+
+	// Choose characters of the active character set by how much of their cell is covered by ink in
+	// Flags.FontFilePath, or Hack-Regular if it isn't set, instead of by their order. Flags.Invert,
+	// Flags.ReverseMap and Flags.Negative flip the mapping the same way they do for the ordered set
+	Coverage bool
+
+	// Draw pixels with an alpha value, from 0 to 255, below this as Flags.TransparentChar instead of
+	// treating them as black. Braille, quadrant and half block characters are only replaced when all of
+	// their pixels are transparent. Disabled when set to 0
+	AlphaThreshold int
+
+	// Character drawn for pixels below Flags.AlphaThreshold. Defaults to a space
+	TransparentChar string
+
+	// RGB color that transparent pixels are blended over before being converted, so logos
+	// can be shown over a light background. Accepts a slice of 3 integers from 0 to 255
+	AlphaBackgroundColor [3]int
+
+	// Choose characters of semi-transparent pixels from their grayscale value weighted by their alpha, instead
+	// of from their color blended over Flags.AlphaBackgroundColor. Anti-aliased edges of transparent logos then
+	// fade into sparse characters, while colors are still blended
+	AlphaWeighted bool
+
+	// Blend transparent pixels over a checkerboard instead of Flags.AlphaBackgroundColor, like image editors do,
+	// to preview which parts of the image are transparent
+	Checkerboard bool
+
+	// RGB values of the two alternating colors of Flags.Checkerboard, e.g. []int{102,102,102,153,153,153}.
+	// Defaults to dark and light gray when nil
+	CheckerColors []int
+
+	// Width and height of each square of Flags.Checkerboard, in pixels of the resized image. For plain ascii
+	// art, this is the number of characters. Defaults to 4 when set to 0
+	CheckerSize int
+
 	// Flip ascii art horizontally
 	FlipX bool
 
 	// Flip ascii art vertically
 	FlipY bool
 
+	// Resampling filter used to shrink the image. Either "nearest", which keeps hard edges of
+	// pixel art, "box", which is fastest for huge images, "linear", "catmull-rom" or "lanczos".
+	// Defaults to "lanczos" when empty
+	ResizeFilter string
+
+	// Height of a terminal character divided by its width, used to keep the image's aspect ratio
+	// when ascii art size is calculated. Fonts in most terminals are twice as tall as they're wide,
+	// so 2.0 should only be changed for unusual fonts. Value provided must be greater than 0
+	FontRatio float64
+
+	// Skip aspect ratio correction for terminal fonts, so each character matches a square of the image
+	// as if Flags.FontRatio was 1. Meant for ascii art that's rendered with square cells elsewhere
+	NoAspectCorrection bool
+
+	// Set how the image is fitted to Flags.Dimensions. Either "stretch", which resizes it to exactly
+	// those and distorts its aspect ratio, "contain", which shrinks it to fit within them while keeping
+	// its aspect ratio, or "cover", which fills them and crops the overflow from its center
+	Fit string
+
+	// Character that ascii art is padded with when Flags.Fit is "contain", so it always takes up
+	// the full Flags.Dimensions with the image centered in them. Defaults to a space
+	PadChar string
+
+	// RGB color used as background of the padding added for Flags.Fit set to "contain", passed as a slice
+	// of 3 integers from 0 to 255 e.g. []int{30,30,30}. Padding isn't colored when this is nil
+	PadColor []int
+
+	// Draw a frame around ascii art. Either "single", "double", "rounded" or "ascii" for styles of
+	// box-drawing characters, or 6 custom characters for the horizontal and vertical edges followed by
+	// the top left, top right, bottom left and bottom right corners, e.g. "=|####". Disabled when empty
+	Border string
+
+	// RGB color of the frame drawn with Flags.Border, passed as a slice of 3 integers from 0 to 255
+	// e.g. []int{255,200,0}. The frame isn't colored when this is nil
+	BorderColor []int
+
+	// Indent ascii art with spaces so it's horizontally centered in the terminal. Ignored when stdout
+	// isn't a terminal, such as when output is redirected to a file
+	Center bool
+
+	// Print blank lines before ascii art so it's vertically centered in the terminal, the same way as Flags.Center
+	CenterVertical bool
+
+	// Set width and height of the terminal ascii art is sized for, passed as a slice of 2 integers
+	// e.g. []int{120,40}. The terminal isn't queried when this is set, which keeps conversions
+	// deterministic where stdout isn't a terminal, such as servers and tests. Without it, a size
+	// of 80x24 is used when there's no terminal to query, and set widths aren't limited by it
+	TerminalSize []int
+
+	// Convert only a region of the image, passed as a slice of 4 integers for its x and y offsets
+	// from the top left corner followed by its width and height, e.g. []int{100,50,300,200}.
+	// The region must lie within the image, and is cropped before the image is resized
+	Crop []int
+
+	// Trim margins of near-uniform color around the image, such as white borders of scans, so ascii art only
+	// covers its content. Margins are trimmed from within Flags.Crop if that's set as well
+	Trim bool
+
+	// Largest difference, from 0 to 255, a color channel can have from the margin's color to still be
+	// trimmed when Flags.Trim is set. Ideal value is 10
+	TrimTolerance int
+
+	// Sigma of the Gaussian blur applied to the image before it's resized, e.g. 1.5. Slight blur reduces
+	// speckles in ascii art of photos with fine texture. 0 leaves the image unblurred
+	Blur float64
+
+	// Amount of sharpening, as the sigma of an unsharp mask, applied after Flags.Blur and before the image is
+	// resized, e.g. 1.0. Recovers crisp edges of logos and text that shrinking softens. 0 leaves it unsharpened
+	Sharpen float64
+
+	// Don't correct the orientation of jpeg images according to their EXIF orientation tag, which phones
+	// use to store photos sideways or upside-down. This keeps the raw pixels as they're stored
+	NoAutoOrient bool
+
+	// Rotate the image clockwise by passed degrees after it's cropped and before it's resized.
+	// Corners exposed by angles that aren't multiples of 90 are filled with Flags.RotateBackgroundColor
+	Rotate float64
+
+	// RGB color for corners exposed by Flags.Rotate. Accepts a slice of 3 integers from 0 to 255
+	RotateBackgroundColor [3]int
+
+	// Convert still images in strips of this many rows of ascii art, writing each one as soon as it's
+	// done instead of converting the whole image at once. This bounds memory usage for very large
+	// images. Saving files isn't supported in this mode. Disabled when set to 0
+	TileRows int
+
 	// Use terminal width to calculate ascii art size while keeping aspect ratio.
 	// This overrides Flags.Dimensions, Flags.Width and Flags.Height
 	Full bool
@@ -81,8 +299,8 @@ type Flags struct {
 	// Font RGB color for terminal display and saved png or gif files.
 	FontColor [3]int
 
-	// Background RGB color in saved png or gif files.
-	// This will be ignored if Flags.SaveImagePath or Flags.SaveGifPath are not set
+	// Background RGB color in saved png, gif, html or svg files. This will be ignored if
+	// Flags.SaveImagePath, Flags.SaveGifPath, Flags.SaveHtmlPath or Flags.SaveSvgPath are not set
 	SaveBackgroundColor [3]int
 
 	// Use braille characters instead of ascii. Terminal must support UTF-8 encoding.
@@ -90,31 +308,282 @@ type Flags struct {
 	// This overrides Flags.Complex and Flags.CustomMap
 	Braille bool
 
-	// Threshold for braille art if Flags.Braille is set to true. Value provided must
-	// be between 0 and 255. Ideal value is 128.
-	// This will be ignored if Flags.Braille is not set
+	// Dots per braille character if Flags.Braille is set. Either 8 for 2x4 dots, or 6 for 2x3 dots
+	// for fonts that render the bottom row of dots poorly. Defaults to 8 when set to 0
+	BrailleDots int
+
+	// Threshold for braille art if Flags.Braille is set to true, or block art if Flags.Quadrant, Flags.Sextant
+	// or Flags.Octant is set to true. Value provided must be between 0 and 255. Ideal value is 128.
+	// This will be ignored if none of Flags.Braille, Flags.Quadrant, Flags.Sextant and Flags.Octant are set.
+	//
+	// Each dot is compared against its own pixel of the image after it's resized to 2x4 (2x2 for quadrant, 2x3 for sextant)
+	// pixels per character, and after gamma, brightness, contrast and dithering are applied to them. Lower
+	// values fill more dots, which suits dark images, while higher values suit light ones
 	Threshold int
+
+	// Strength of dithering applied before characters are mapped.
+	// Value provided must be between 0.0 and 1.0. Dithering is disabled when set to 0
+	Dithering float64
+
+	// Algorithm used for dithering. Either "floyd-steinberg", "atkinson" or "bayer".
+	// Defaults to "floyd-steinberg" when empty.
+	// This will be ignored if Flags.Dithering is not set
+	DitherMode string
+
+	// Size of the threshold matrix for "bayer" dither mode. Either 2, 4 or 8.
+	// This will be ignored if Flags.DitherMode is not "bayer"
+	BayerSize int
+
+	// Gamma correction applied to grayscale values before characters are mapped.
+	// Values above 1.0 brighten shadows, while values below 1.0 darken them.
+	// Value provided must be greater than 0. 1.0 leaves the image unchanged
+	Gamma float64
+
+	// Brightness shift applied after Flags.Gamma, to grayscale values as well as colors.
+	// Value provided must be between -100 and 100. 0 leaves the image unchanged
+	Brightness float64
+
+	// Factor by which grayscale values and colors are scaled around the midpoint after
+	// Flags.Brightness, e.g. 1.5 for higher contrast. Value provided must be greater than 0.
+	// 1.0 leaves the image unchanged
+	Contrast float64
+
+	// Formula used to calculate brightness, which decides characters, from colors. Either "rec601",
+	// "rec709" or "average" for equal weights. Defaults to Go's grayscale model, close to "rec601", when empty
+	Luminance string
+
+	// Custom red, green and blue weights for brightness, overriding Flags.Luminance, e.g. []float64{0.6,0.3,0.1}
+	// to bring out red subjects. Weights are scaled to sum to 1, and can't be negative. Unused when nil
+	LuminanceWeights []float64
+
+	// Keep 16-bit precision of brightness values while resizing, which gives smoother ramps for 16-bit PNGs
+	// and TIFFs mapped against long character sets such as Flags.Complex. Colors stay 8-bit, and pixels
+	// changed by adjustments or dithering lose the extra precision. Slower than the default 8-bit path
+	HighPrecision bool
+
+	// Resize images in linear light instead of sRGB, so bright details of large images shrunk to terminal
+	// size keep their brightness instead of darkening. Slower than the default resize
+	LinearResize bool
+
+	// Spread brightness values evenly across all characters through histogram equalization before
+	// Flags.Gamma, Flags.Brightness and Flags.Contrast are applied. Helps low-contrast images where most
+	// pixels would otherwise map to the same few characters. Colors are left unchanged
+	Equalize bool
+
+	// Factor by which the saturation of colors is scaled, e.g. 0.8 for slightly muted colors.
+	// Only affects colored ascii art, and value provided must be greater than 0. 1.0 leaves colors unchanged
+	Saturation float64
+
+	// Tint colors with a warm sepia tone, blended with the original colors by this percentage between
+	// 0 and 100. Only affects colored ascii art, so characters are still chosen by true brightness. 0 leaves colors unchanged
+	Sepia float64
+
+	// Reduce brightness and colors to this many levels, from 2 to 256, after other adjustments for a bold,
+	// poster-like look that maps cleanly onto short character sets. Disabled when set to 0
+	Posterize int
+
+	// Use upper half block characters with both foreground and background colors, so
+	// each character represents two vertical pixels. Uses grayscale colors if Flags.Colored
+	// is not set. Terminal must support True color and UTF-8 encoding.
+	// This overrides Flags.Complex and Flags.CustomMap, and is overridden by Flags.Braille
+	HalfBlock bool
+
+	// Use spaces with background colors instead of characters, so each character is a solid block of its pixel's
+	// color. Uses grayscale colors if Flags.Colored is not set, and Flags.ColorMode decides the palette.
+	// This overrides Flags.Complex, Flags.CustomMap and Flags.EdgeMode, and is overridden by Flags.Braille,
+	// Flags.Octant, Flags.Sextant, Flags.Quadrant and Flags.HalfBlock
+	BgBlock bool
+
+	// Use quadrant block characters so each character represents a 2x2 block of pixels.
+	// Flags.Threshold is used to decide which quadrants are filled.
+	// This overrides Flags.Complex, Flags.CustomMap and Flags.HalfBlock, and is overridden by Flags.Braille
+	Quadrant bool
+
+	// Use sextant characters from the Symbols for Legacy Computing block so each character represents a 2x3 block
+	// of pixels. Flags.Threshold is used to decide which sextants are filled. Terminal font must support them.
+	// This overrides Flags.Complex, Flags.CustomMap, Flags.HalfBlock and Flags.Quadrant, and is overridden by Flags.Braille and Flags.Octant
+	Sextant bool
+
+	// Use octant characters from the Symbols for Legacy Computing Supplement block so each character represents a 2x4
+	// block of pixels, like braille but with solid blocks instead of dots. Flags.Threshold is used to decide which
+	// octants are filled. Terminal font must support them. This overrides Flags.Complex, Flags.CustomMap,
+	// Flags.HalfBlock, Flags.Quadrant and Flags.Sextant, and is overridden by Flags.Braille
+	Octant bool
+
+	// Draw edges of the image with line characters instead of mapping its brightness, which works
+	// best for diagrams and logos. Either "sobel", "canny" for thinner and cleaner edges on
+	// noisy images, or empty for regular ascii art.
+	// This overrides Flags.Complex and Flags.CustomMap, and is overridden by Flags.Braille,
+	// Flags.Quadrant and Flags.HalfBlock
+	EdgeMode string
+
+	// Gradient strength a pixel needs to be drawn as an edge if Flags.EdgeMode is set.
+	// For "canny", this is the high threshold. Value provided must be between 0 and 255. Ideal value is 64
+	EdgeThreshold int
+
+	// Gradient strength a pixel connected to an edge needs to be drawn as one too, if Flags.EdgeMode
+	// is "canny". Value provided must be between 0 and Flags.EdgeThreshold. 0 uses half of Flags.EdgeThreshold
+	EdgeLowThreshold int
+
+	// Sigma of the blur applied before finding edges if Flags.EdgeMode is "canny". Higher values
+	// ignore more noise. 0 uses the ideal value of 1.4
+	EdgeBlur float64
+
+	// Display the image itself through a terminal graphics protocol instead of ascii art,
+	// covering as many characters as its ascii art would. Either "sixel", "kitty", "iterm" or
+	// "auto", which picks whichever the terminal supports and falls back to ascii art otherwise.
+	// Saved .txt, .png and .gif files will still contain ascii art.
+	// This is not supported for gifs
+	Graphics string
+
+	// Escape codes used for colored ascii art in the terminal. Either "truecolor", "256" for the
+	// nearest colors of the xterm 256 color palette, "16" or "8" for the nearest standard ANSI
+	// colors with or without their bright variants, "plain" for no colors at all, or "auto" to pick
+	// the richest mode advertised by the COLORTERM and TERM environment variables.
+	// Defaults to "truecolor" when empty. Saved files always keep the original colors
+	ColorMode string
+
+	// Metric used to find the nearest palette colors for the "256", "16" and "8" color modes. Either
+	// "ciede2000" for the most accurate colors, "cie76" or "redmean" for the fastest matching.
+	// Defaults to "ciede2000" when empty
+	ColorDistance string
+
+	// Time limit for fetching an image or gif from a url, including reading its contents.
+	// Defaults to 30 seconds when set to 0
+	URLTimeout time.Duration
+
+	// User-Agent header sent when fetching an image or gif from a url.
+	// Defaults to "ascii-image-converter" when empty
+	UserAgent string
+
+	// Largest file size in bytes that will be downloaded when fetching an image or gif from a url.
+	// Defaults to 50 MiB when set to 0
+	MaxDownloadSize int64
+
+	// Number of images converted concurrently by ConvertDir(). Defaults to Flags.MaxConcurrency when set to 0
+	BatchWorkers int
+
+	// Maximum number of goroutines used for converting, which caps CPU usage on shared machines or containers
+	// with CPU limits. It's split between images converted concurrently by ConvertDir() or gif frames, and
+	// the pixels of each of them. Defaults to the number of CPUs when set to 0
+	MaxConcurrency int
+
+	// Called after each image or gif frame is converted, e.g. to display a progress bar or find slow frames.
+	// Calls never overlap, but may come from different goroutines and, for gifs, out of frame order. Unused when nil
+	Progress func(Stats)
 }
 
 var (
-	dimensions    []int
-	width         int
-	height        int
-	complex       bool
-	saveTxtPath   string
-	saveImagePath string
-	saveGifPath   string
-	grayscale     bool
-	negative      bool
-	colored       bool
-	colorBg       bool
-	customMap     string
-	flipX         bool
-	flipY         bool
-	full          bool
-	fontPath      string
-	fontColor     [3]int
-	saveBgColor   [3]int
-	braille       bool
-	threshold     int
+	dimensions         []int
+	width              int
+	height             int
+	complex            bool
+	saveTxtPath        string
+	saveJsonPath       string
+	saveImagePath      string
+	saveGifPath        string
+	gifClear           bool
+	gifLoop            bool
+	loopCount          int
+	fps                float64
+	saveHtmlPath       string
+	htmlFont           string
+	saveSvgPath        string
+	svgCellSize        [2]int
+	svgFont            string
+	grayscale          bool
+	negative           bool
+	invert             bool
+	theme              string
+	colored            bool
+	gradient           [2][3]int
+	useGradient        bool
+	rainbow            string
+	quantize           int
+	quantizeMethod     string
+	colorPalette       string
+	customPalette      [][3]int
+	colorBg            bool
+	dualColor          bool
+	customMap          string
+	reverseMap         bool
+	coverageMatcher    *imgManip.CoverageMatcher
+	alphaThreshold     int
+	transparentChar    string
+	alphaBgColor       [3]int
+	alphaWeighted      bool
+	checkerboard       bool
+	checkerColors      [2][3]int
+	checkerSize        int
+	flipX              bool
+	flipY              bool
+	full               bool
+	tileRows           int
+	crop               image.Rectangle
+	trim               bool
+	trimTolerance      int
+	blur               float64
+	sharpen            float64
+	noAutoOrient       bool
+	rotate             float64
+	rotateBgColor      [3]int
+	resizeFilter       string
+	fontRatio          float64
+	noAspectCorrection bool
+	fit                string
+	padChar            string
+	padColor           *[3]int
+	borderChars        *[6]string
+	borderColor        *[3]int
+	center             bool
+	centerVertical     bool
+	terminalSize       [2]int
+	fontPath           string
+	fontColor          [3]int
+	saveBgColor        [3]int
+	braille            bool
+	brailleDots        int
+	threshold          int
+	dithering          float64
+	ditherMode         string
+	bayerSize          int
+	gamma              float64
+	brightness         float64
+	contrast           float64
+	saturation         float64
+	sepia              float64
+	posterize          int
+	equalize           bool
+	luminance          string
+	luminanceWeights   [3]float64
+	highPrecision      bool
+	linearResize       bool
+	halfBlock          bool
+	bgBlock            bool
+	quadrant           bool
+	sextant            bool
+	octant             bool
+	edgeMode           string
+	edgeThreshold      int
+	edgeLowThreshold   int
+	edgeBlur           float64
+	graphics           string
+	graphicsDetected   bool
+	colorMode          string
+	colorDistance      string
+	urlTimeout         time.Duration
+	userAgent          string
+	maxDownloadSize    int64
+	batchWorkers       int
+	maxConcurrency     int
+	pixelConcurrency   int
+	progress           func(Stats)
+)
+
+// Errors that conversions may return wrapped with more context, so they can be checked with errors.Is()
+var (
+	ErrWidthExceedsTerminal = imgManip.ErrWidthExceedsTerminal
+	ErrWidthAndHeightSet    = imgManip.ErrWidthAndHeightSet
+	ErrInvalidFontRatio     = imgManip.ErrInvalidFontRatio
+	ErrInvalidDimensions    = imgManip.ErrInvalidDimensions
 )
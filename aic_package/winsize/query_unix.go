@@ -0,0 +1,82 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package winsize
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Returns the width and height of a single terminal character in pixels. Not all terminals
+// report their pixel dimensions, in which case an error is returned
+func GetCellSize() (int, int, error) {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tty.Close()
+
+	ws, err := unix.IoctlGetWinsize(int(tty.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if ws.Col == 0 || ws.Row == 0 || ws.Xpixel == 0 || ws.Ypixel == 0 {
+		return 0, 0, fmt.Errorf("terminal doesn't report its size in pixels")
+	}
+
+	return int(ws.Xpixel / ws.Col), int(ws.Ypixel / ws.Row), nil
+}
+
+// Writes query to the terminal and returns its response up to and including the terminator byte.
+// Returns an error if the terminal doesn't respond in time, since not all terminals support every query
+func QueryTerminal(query string, terminator byte) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", err
+	}
+	defer tty.Close()
+
+	fd := int(tty.Fd())
+
+	oldState, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return "", err
+	}
+
+	// Disable echo and line buffering so the response can be read as soon as it arrives,
+	// and time out reads after 200ms in case the terminal never responds
+	newState := *oldState
+	newState.Lflag &^= unix.ECHO | unix.ICANON
+	newState.Cc[unix.VMIN] = 0
+	newState.Cc[unix.VTIME] = 2
+
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &newState); err != nil {
+		return "", err
+	}
+	defer unix.IoctlSetTermios(fd, ioctlSetTermios, oldState)
+
+	if _, err := tty.WriteString(query); err != nil {
+		return "", err
+	}
+
+	var response []byte
+	buf := make([]byte, 64)
+
+	for {
+		n, err := tty.Read(buf)
+		if err != nil || n == 0 {
+			return string(response), fmt.Errorf("terminal didn't respond to query")
+		}
+
+		for _, c := range buf[:n] {
+			response = append(response, c)
+			if c == terminator {
+				return string(response), nil
+			}
+		}
+	}
+}
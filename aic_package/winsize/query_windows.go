@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package winsize
+
+import "fmt"
+
+// Returns the width and height of a single terminal character in pixels. This functionality
+// isn't supported for windows yet
+func GetCellSize() (int, int, error) {
+	return 0, 0, fmt.Errorf("getting character size in pixels isn't currently supported on windows")
+}
+
+// Writes query to the terminal and returns its response. This functionality isn't supported
+// for windows yet
+func QueryTerminal(query string, terminator byte) (string, error) {
+	return "", fmt.Errorf("querying the terminal isn't currently supported on windows")
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"time"
 
 	"github.com/TheZoraiz/ascii-image-converter/aic_package"
 
@@ -29,28 +33,106 @@ import (
 
 var (
 	// Flags
-	cfgFile       string
-	complex       bool
-	dimensions    []int
-	width         int
-	height        int
-	saveTxtPath   string
-	saveImagePath string
-	saveGifPath   string
-	negative      bool
-	formatsTrue   bool
-	colored       bool
-	colorBg       bool
-	grayscale     bool
-	customMap     string
-	flipX         bool
-	flipY         bool
-	full          bool
-	fontFile      string
-	fontColor     []int
-	saveBgColor   []int
-	braille       bool
-	threshold     int
+	cfgFile            string
+	complex            bool
+	dimensions         []int
+	width              int
+	height             int
+	saveTxtPath        string
+	saveJsonPath       string
+	saveImagePath      string
+	saveGifPath        string
+	gifClear           bool
+	gifLoop            bool
+	loopCount          int
+	fps                float64
+	maxConcurrency     int
+	saveHtmlPath       string
+	htmlFont           string
+	saveSvgPath        string
+	svgCellSize        []int
+	svgFont            string
+	negative           bool
+	invert             bool
+	theme              string
+	formatsTrue        bool
+	colored            bool
+	gradient           []int
+	rainbow            string
+	quantize           int
+	quantizeMethod     string
+	colorPalette       string
+	customPalette      []int
+	colorBg            bool
+	dualColor          bool
+	grayscale          bool
+	customMap          string
+	reverseMap         bool
+	coverage           bool
+	alphaThreshold     int
+	transparentChar    string
+	alphaBgColor       []int
+	alphaWeighted      bool
+	checkerboard       bool
+	checkerColors      []int
+	checkerSize        int
+	flipX              bool
+	flipY              bool
+	full               bool
+	tileRows           int
+	crop               []int
+	trim               bool
+	trimTolerance      int
+	blur               float64
+	sharpen            float64
+	noAutoOrient       bool
+	rotate             float64
+	rotateBgColor      []int
+	resizeFilter       string
+	fontRatio          float64
+	noAspectCorrection bool
+	fit                string
+	padChar            string
+	padColor           []int
+	border             string
+	borderColor        []int
+	center             bool
+	centerVertical     bool
+	fontFile           string
+	fontColor          []int
+	saveBgColor        []int
+	braille            bool
+	brailleDots        int
+	threshold          int
+	dithering          float64
+	ditherMode         string
+	bayerSize          int
+	gamma              float64
+	brightness         float64
+	contrast           float64
+	saturation         float64
+	sepia              float64
+	posterize          int
+	equalize           bool
+	luminance          string
+	luminanceWeights   []float64
+	highPrecision      bool
+	linearResize       bool
+	halfBlock          bool
+	bgBlock            bool
+	quadrant           bool
+	sextant            bool
+	octant             bool
+	edgeMode           string
+	edgeThreshold      int
+	edgeLowThreshold   int
+	edgeBlur           float64
+	graphics           string
+	colorMode          string
+	colorDistance      string
+	urlTimeout         time.Duration
+	userAgent          string
+	maxDownload        int
 
 	// Root commands
 	rootCmd = &cobra.Command{
@@ -67,32 +149,117 @@ var (
 			}
 
 			flags := aic_package.Flags{
-				Complex:             complex,
-				Dimensions:          dimensions,
-				Width:               width,
-				Height:              height,
-				SaveTxtPath:         saveTxtPath,
-				SaveImagePath:       saveImagePath,
-				SaveGifPath:         saveGifPath,
-				Negative:            negative,
-				Colored:             colored,
-				CharBackgroundColor: colorBg,
-				Grayscale:           grayscale,
-				CustomMap:           customMap,
-				FlipX:               flipX,
-				FlipY:               flipY,
-				Full:                full,
-				FontFilePath:        fontFile,
-				FontColor:           [3]int{fontColor[0], fontColor[1], fontColor[2]},
-				SaveBackgroundColor: [3]int{saveBgColor[0], saveBgColor[1], saveBgColor[2]},
-				Braille:             braille,
-				Threshold:           threshold,
+				Complex:               complex,
+				Dimensions:            dimensions,
+				Width:                 width,
+				Height:                height,
+				SaveTxtPath:           saveTxtPath,
+				SaveJsonPath:          saveJsonPath,
+				SaveImagePath:         saveImagePath,
+				SaveGifPath:           saveGifPath,
+				GifClear:              gifClear,
+				GifLoop:               gifLoop,
+				LoopCount:             loopCount,
+				FPS:                   fps,
+				SaveHtmlPath:          saveHtmlPath,
+				HtmlFont:              htmlFont,
+				SaveSvgPath:           saveSvgPath,
+				SvgCellSize:           [2]int{svgCellSize[0], svgCellSize[1]},
+				SvgFont:               svgFont,
+				Negative:              negative,
+				Invert:                invert,
+				Theme:                 theme,
+				Colored:               colored,
+				Gradient:              gradient,
+				Rainbow:               rainbow,
+				Quantize:              quantize,
+				QuantizeMethod:        quantizeMethod,
+				Palette:               colorPalette,
+				CustomPalette:         customPalette,
+				CharBackgroundColor:   colorBg,
+				DualColor:             dualColor,
+				Grayscale:             grayscale,
+				CustomMap:             customMap,
+				ReverseMap:            reverseMap,
+				Coverage:              coverage,
+				AlphaThreshold:        alphaThreshold,
+				TransparentChar:       transparentChar,
+				AlphaBackgroundColor:  [3]int{alphaBgColor[0], alphaBgColor[1], alphaBgColor[2]},
+				AlphaWeighted:         alphaWeighted,
+				Checkerboard:          checkerboard,
+				CheckerColors:         checkerColors,
+				CheckerSize:           checkerSize,
+				FlipX:                 flipX,
+				FlipY:                 flipY,
+				Full:                  full,
+				TileRows:              tileRows,
+				Crop:                  crop,
+				Trim:                  trim,
+				TrimTolerance:         trimTolerance,
+				Blur:                  blur,
+				Sharpen:               sharpen,
+				NoAutoOrient:          noAutoOrient,
+				Rotate:                rotate,
+				RotateBackgroundColor: [3]int{rotateBgColor[0], rotateBgColor[1], rotateBgColor[2]},
+				ResizeFilter:          resizeFilter,
+				FontRatio:             fontRatio,
+				NoAspectCorrection:    noAspectCorrection,
+				Fit:                   fit,
+				PadChar:               padChar,
+				PadColor:              padColor,
+				Border:                border,
+				BorderColor:           borderColor,
+				Center:                center,
+				CenterVertical:        centerVertical,
+				FontFilePath:          fontFile,
+				FontColor:             [3]int{fontColor[0], fontColor[1], fontColor[2]},
+				SaveBackgroundColor:   [3]int{saveBgColor[0], saveBgColor[1], saveBgColor[2]},
+				Braille:               braille,
+				BrailleDots:           brailleDots,
+				Threshold:             threshold,
+				Dithering:             dithering,
+				DitherMode:            ditherMode,
+				BayerSize:             bayerSize,
+				Gamma:                 gamma,
+				Brightness:            brightness,
+				Contrast:              contrast,
+				Saturation:            saturation,
+				Sepia:                 sepia,
+				Posterize:             posterize,
+				Equalize:              equalize,
+				Luminance:             luminance,
+				LuminanceWeights:      luminanceWeights,
+				HighPrecision:         highPrecision,
+				LinearResize:          linearResize,
+				HalfBlock:             halfBlock,
+				BgBlock:               bgBlock,
+				Quadrant:              quadrant,
+				Sextant:               sextant,
+				Octant:                octant,
+				EdgeMode:              edgeMode,
+				EdgeThreshold:         edgeThreshold,
+				EdgeLowThreshold:      edgeLowThreshold,
+				EdgeBlur:              edgeBlur,
+				Graphics:              graphics,
+				ColorMode:             colorMode,
+				ColorDistance:         colorDistance,
+				URLTimeout:            urlTimeout,
+				UserAgent:             userAgent,
+				MaxDownloadSize:       int64(maxDownload) << 20,
+				MaxConcurrency:        maxConcurrency,
 			}
 
+			// Interrupting stops gif playback gracefully, so the terminal's cursor is restored
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
 			for _, imagePath := range args {
 
-				if asciiArt, err := aic_package.Convert(imagePath, flags); err == nil {
+				if asciiArt, err := aic_package.ConvertWithContext(ctx, imagePath, flags); err == nil {
 					fmt.Printf("%s", asciiArt)
+				} else if errors.Is(err, context.Canceled) {
+					fmt.Println()
+					return
 				} else {
 					fmt.Printf("Error: %v\n", err)
 
@@ -127,24 +294,102 @@ func init() {
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.ascii-image-converter.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&colored, "color", "C", false, "Display ascii art with original colors\n(Inverts with --negative flag)\n(Overrides --grayscale and --font-color flags)\n")
 	rootCmd.PersistentFlags().BoolVar(&colorBg, "color-bg", false, "If some color flag is passed, use that color\non character background instead of foreground\n(Inverts with --negative flag)\n(Doesn't work for --save-img or --save-gif)\n")
+	rootCmd.PersistentFlags().BoolVar(&dualColor, "dual-color", false, "If --color or --grayscale is passed, color\ncharacter backgrounds with darkened colors\nand characters with lightened ones\n(Overrides --color-bg flag)\n(Doesn't work for --save-img or --save-gif)\n")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color-mode", "truecolor", "Set escape codes used for colors in terminal\nEither truecolor, 256, 16, 8, plain or auto\ne.g. --color-mode 256\n(Defaults to truecolor)\n")
+	rootCmd.PersistentFlags().StringVar(&colorDistance, "color-distance", "ciede2000", "Set how nearest colors are matched for --color-mode 256, 16 and 8\nEither ciede2000, cie76 or redmean, from most accurate to fastest\ne.g. --color-distance redmean\n(Defaults to ciede2000)\n")
 	rootCmd.PersistentFlags().IntSliceVarP(&dimensions, "dimensions", "d", nil, "Set width and height for ascii art in CHARACTER length\ne.g. -d 60,30 (defaults to terminal height)\n(Overrides --width and --height flags)\n")
 	rootCmd.PersistentFlags().IntVarP(&width, "width", "W", 0, "Set width for ascii art in CHARACTER length\nHeight is kept to aspect ratio\ne.g. -W 60\n")
 	rootCmd.PersistentFlags().IntVarP(&height, "height", "H", 0, "Set height for ascii art in CHARACTER length\nWidth is kept to aspect ratio\ne.g. -H 60\n")
 	rootCmd.PersistentFlags().StringVarP(&customMap, "map", "m", "", "Give custom ascii characters to map against\nOrdered from darkest to lightest\ne.g. -m \" .-+#@\" (Quotation marks excluded from map)\n(Overrides --complex flag)\n")
-	rootCmd.PersistentFlags().BoolVarP(&braille, "braille", "b", false, "Use braille characters instead of ascii\nTerminal must support braille patterns properly\n(Overrides --complex and --map flags)\n")
-	rootCmd.PersistentFlags().IntVar(&threshold, "threshold", 0, "Threshold for braille art\nValue between 0-255 is accepted\ne.g. --threshold 170\n(Defaults to 128)\n")
+	rootCmd.PersistentFlags().BoolVar(&reverseMap, "reverse-map", false, "Reverse the order of ascii characters\nUsed for the default set or --map flag\n(Doesn't work with --braille flag)\n")
+	rootCmd.PersistentFlags().BoolVar(&coverage, "coverage", false, "Choose ascii characters by how much ink\ntheir glyphs cover in --font or Hack-Regular\ninstead of by their order in the character set\n(Doesn't work with --braille flag)\n")
+	rootCmd.PersistentFlags().IntVar(&alphaThreshold, "alpha-threshold", 0, "Draw pixels with alpha below this value\nas --transparent-char instead of black\nValue between 0-255 is accepted\ne.g. --alpha-threshold 128\n(Disabled by default)\n")
+	rootCmd.PersistentFlags().StringVar(&transparentChar, "transparent-char", " ", "Set character for transparent pixels\nUsed with --alpha-threshold flag\ne.g. --transparent-char \".\"\n(Defaults to a space)\n")
+	rootCmd.PersistentFlags().IntSliceVar(&alphaBgColor, "alpha-bg", nil, "Set color that transparent pixels\nare blended over before conversion\nPass an RGB value\ne.g. --alpha-bg 255,255,255\n(Defaults to 0,0,0)\n")
+	rootCmd.PersistentFlags().BoolVar(&alphaWeighted, "alpha-weighted", false, "Choose characters of semi-transparent pixels\nfrom their brightness weighted by alpha\ninstead of their color over --alpha-bg\nGives transparent logos soft edges\n")
+	rootCmd.PersistentFlags().BoolVar(&checkerboard, "checkerboard", false, "Blend transparent pixels over a checkerboard\ninstead of --alpha-bg to preview transparency\n")
+	rootCmd.PersistentFlags().IntSliceVar(&checkerColors, "checker-colors", nil, "Set colors of --checkerboard squares\nPass RGB values of both colors\ne.g. --checker-colors 0,0,0,255,255,255\n(Defaults to 102,102,102,153,153,153)\n")
+	rootCmd.PersistentFlags().IntVar(&checkerSize, "checker-size", 4, "Set size of --checkerboard squares\nin pixels of the resized image\ne.g. --checker-size 2\n(Defaults to 4)\n")
+	rootCmd.PersistentFlags().BoolVarP(&braille, "braille", "b", false, "Use braille characters instead of ascii\nTerminal must support braille patterns properly\n(Overrides --complex, --map, --half-block, --quadrant, --sextant and --octant flags)\n")
+	rootCmd.PersistentFlags().IntVar(&brailleDots, "braille-dots", 8, "Set dots per braille character\nEither 8 for 2x4 dots or 6 for 2x3 dots\nfor fonts with a cramped bottom row\ne.g. --braille-dots 6\n(Defaults to 8)\n")
+	rootCmd.PersistentFlags().BoolVar(&halfBlock, "half-block", false, "Use half block characters with both foreground\nand background colors, doubling vertical resolution\nUses grayscale colors unless --color is passed\n(Overrides --complex and --map flags)\n")
+	rootCmd.PersistentFlags().BoolVar(&bgBlock, "bg-block", false, "Use spaces with background colors instead\nof characters, drawing the image as solid blocks\nUses grayscale colors unless --color is passed\n(Overrides --complex, --map and --edges flags)\n")
+	rootCmd.PersistentFlags().BoolVar(&quadrant, "quadrant", false, "Use quadrant block characters so each\ncharacter represents a 2x2 block of pixels\n(Overrides --complex, --map and --half-block flags)\n")
+	rootCmd.PersistentFlags().BoolVar(&sextant, "sextant", false, "Use sextant characters so each character\nrepresents a 2x3 block of pixels\nTerminal font must support them\n(Overrides --complex, --map, --half-block and --quadrant flags)\n")
+	rootCmd.PersistentFlags().BoolVar(&octant, "octant", false, "Use octant characters so each character\nrepresents a 2x4 block of pixels\nTerminal font must support them\n(Overrides --complex, --map, --half-block, --quadrant and --sextant flags)\n")
+	rootCmd.PersistentFlags().StringVar(&edgeMode, "edges", "", "Draw edges of the image with line characters\ninstead of mapping brightness\nEither sobel or canny\ne.g. --edges sobel\n(Overrides --complex and --map flags)\n")
+	rootCmd.PersistentFlags().IntVar(&edgeThreshold, "edge-threshold", 64, "Gradient strength needed for --edges flag\nValue between 0-255 is accepted\ne.g. --edge-threshold 40\n(Defaults to 64)\n")
+	rootCmd.PersistentFlags().IntVar(&edgeLowThreshold, "edge-low-threshold", 0, "Low threshold for --edges canny\nConnected pixels above it are kept\ne.g. --edge-low-threshold 20\n(Defaults to half of --edge-threshold)\n")
+	rootCmd.PersistentFlags().Float64Var(&edgeBlur, "edge-blur", 1.4, "Blur sigma for --edges canny\nHigher values ignore more noise\ne.g. --edge-blur 2\n(Defaults to 1.4)\n")
+	rootCmd.PersistentFlags().IntVar(&threshold, "threshold", 0, "Threshold for braille, quadrant, sextant\nand octant art\nValue between 0-255 is accepted\ne.g. --threshold 170\n(Defaults to 128)\n")
+	rootCmd.PersistentFlags().Float64Var(&dithering, "dither", 0, "Apply dithering before mapping characters\nPass a strength between 0.0 and 1.0\ne.g. --dither 0.8\n(Works with --braille flag as well)\n")
+	rootCmd.PersistentFlags().StringVar(&ditherMode, "dither-mode", "", "Set algorithm for --dither flag\nEither floyd-steinberg, atkinson or bayer\ne.g. --dither-mode atkinson\n(Defaults to floyd-steinberg)\n")
+	rootCmd.PersistentFlags().IntVar(&bayerSize, "bayer-size", 4, "Set matrix size for bayer dither mode\nEither 2, 4 or 8\ne.g. --bayer-size 8\n(Defaults to 4)\n")
+	rootCmd.PersistentFlags().Float64Var(&gamma, "gamma", 1, "Apply gamma correction before mapping characters\nValues above 1.0 brighten shadows\ne.g. --gamma 1.8\n(Defaults to 1.0)\n")
+	rootCmd.PersistentFlags().Float64Var(&brightness, "brightness", 0, "Shift brightness before mapping characters\nValue between -100 and 100 is accepted\ne.g. --brightness 20\n(Applied after --gamma)\n")
+	rootCmd.PersistentFlags().Float64Var(&contrast, "contrast", 1, "Scale contrast before mapping characters\nValues above 1.0 increase contrast\ne.g. --contrast 1.5\n(Applied after --brightness)\n")
+	rootCmd.PersistentFlags().Float64Var(&saturation, "saturation", 1, "Scale saturation of colors\nValues below 1.0 mute colors\ne.g. --saturation 0.8\n(Only works with --color flag)\n")
+	rootCmd.PersistentFlags().Float64Var(&sepia, "sepia", 0, "Tint colors with a sepia tone\nValue between 0 and 100 is accepted\ne.g. --sepia 80\n(Only works with --color flag)\n")
+	rootCmd.PersistentFlags().IntVar(&posterize, "posterize", 0, "Reduce brightness and colors to passed levels\nValue between 2-256 is accepted\ne.g. --posterize 4\n(Disabled by default)\n")
+	rootCmd.PersistentFlags().BoolVar(&equalize, "equalize", false, "Spread brightness evenly across characters\nthrough histogram equalization\n(Applied before --gamma)\n")
+	rootCmd.PersistentFlags().StringVar(&luminance, "luminance", "", "Set formula for brightness of colors\nEither rec601, rec709 or average\ne.g. --luminance rec709\n(Defaults to Go's grayscale model)\n")
+	rootCmd.PersistentFlags().Float64SliceVar(&luminanceWeights, "luminance-weights", nil, "Set custom red, green and blue weights\nfor brightness of colors, overriding --luminance\ne.g. --luminance-weights 0.6,0.3,0.1\n(Scaled to sum to 1)\n")
+	rootCmd.PersistentFlags().BoolVar(&highPrecision, "high-precision", false, "Keep 16-bit brightness values of images\nfor smoother ramps with long character sets\n(Slower than the default)\n")
+	rootCmd.PersistentFlags().BoolVar(&linearResize, "linear-resize", false, "Resize images in linear light instead of sRGB\nso bright details aren't darkened\n(Slower than the default)\n")
 	rootCmd.PersistentFlags().BoolVarP(&grayscale, "grayscale", "g", false, "Display grayscale ascii art\n(Inverts with --negative flag)\n(Overrides --font-color flag)\n")
+	rootCmd.PersistentFlags().IntSliceVar(&gradient, "gradient", nil, "Color characters along a gradient by brightness\nPass RGB values of the darkest and brightest colors\ne.g. --gradient 20,0,80,255,200,0\n(Overrides --color and --grayscale flags)\n")
+	rootCmd.PersistentFlags().StringVar(&rainbow, "rainbow", "", "Color characters with cycling rainbow hues\nEither column, row or brightness\ne.g. --rainbow column\n(Overrides --gradient, --color and --grayscale flags)\n")
+	rootCmd.PersistentFlags().IntVar(&quantize, "quantize", 0, "Reduce colors of ascii art to at most\npassed number of colors\nValue between 2-256 is accepted\ne.g. --quantize 8\n(Disabled by default)\n")
+	rootCmd.PersistentFlags().StringVar(&quantizeMethod, "quantize-method", "median-cut", "Set how the palette of --quantize is generated\nEither median-cut or k-means, which is\nslower but closer to the image's colors\ne.g. --quantize-method k-means\n(Defaults to median-cut)\n")
+	rootCmd.PersistentFlags().StringVar(&colorPalette, "palette", "", "Map colors to the nearest ones of a retro palette\nEither cga, gameboy, c64 or nes\ne.g. --palette gameboy\n")
+	rootCmd.PersistentFlags().IntSliceVar(&customPalette, "custom-palette", nil, "Map colors to the nearest ones of passed colors\nPass RGB values of each color\ne.g. --custom-palette 0,0,0,255,0,0,255,255,255\n(Overrides --palette flag)\n")
+	rootCmd.PersistentFlags().StringVar(&graphics, "graphics", "", "Display the image itself through a terminal\ngraphics protocol instead of ascii art\nEither sixel, kitty, iterm or auto\ne.g. --graphics auto\n(Doesn't work for gifs)\n")
 	rootCmd.PersistentFlags().BoolVarP(&complex, "complex", "c", false, "Display ascii characters in a larger range\nMay result in higher quality\n")
 	rootCmd.PersistentFlags().BoolVarP(&full, "full", "f", false, "Use largest dimensions for ascii art\nthat fill the terminal width\n(Overrides --dimensions, --width and --height flags)\n")
+	rootCmd.PersistentFlags().IntVar(&tileRows, "tile-rows", 0, "Convert images in strips of passed rows\nprinting each as soon as it's done\nLowers memory usage for huge images\ne.g. --tile-rows 10\n(Doesn't work with saving flags)\n")
+	rootCmd.PersistentFlags().IntSliceVar(&crop, "crop", nil, "Convert only a region of the image\nPass x and y offsets, width and height in pixels\ne.g. --crop 100,50,300,200\n(Applied before resizing)\n")
+	rootCmd.PersistentFlags().BoolVar(&trim, "trim", false, "Trim margins of near-uniform color\naround the image before converting it\n(Applied after --crop)\n")
+	rootCmd.PersistentFlags().IntVar(&trimTolerance, "trim-tolerance", 10, "Color difference still trimmed by --trim\nValue between 0-255 is accepted\ne.g. --trim-tolerance 30\n(Defaults to 10)\n")
+	rootCmd.PersistentFlags().BoolVar(&noAutoOrient, "no-auto-orient", false, "Don't rotate jpeg photos according to\ntheir EXIF orientation tag\n")
+	rootCmd.PersistentFlags().Float64Var(&rotate, "rotate", 0, "Rotate the image clockwise by passed degrees\ne.g. --rotate 90\n(Applied after --crop)\n")
+	rootCmd.PersistentFlags().Float64Var(&blur, "blur", 0, "Blur the image before resizing to reduce noise\nPass the sigma of the Gaussian blur\ne.g. --blur 1.5\n(Applied after --rotate)\n")
+	rootCmd.PersistentFlags().Float64Var(&sharpen, "sharpen", 0, "Sharpen the image before resizing\nPass the sigma of the unsharp mask\ne.g. --sharpen 1.0\n(Applied after --blur)\n")
+	rootCmd.PersistentFlags().IntSliceVar(&rotateBgColor, "rotate-bg", nil, "Set color of corners exposed by --rotate\nPass an RGB value\ne.g. --rotate-bg 255,255,255\n(Defaults to 0,0,0)\n")
+	rootCmd.PersistentFlags().StringVar(&resizeFilter, "resize-filter", "lanczos", "Set filter used to shrink the image\nEither nearest, box, linear, catmull-rom or lanczos\ne.g. --resize-filter nearest\n(Defaults to lanczos)\n")
+	rootCmd.PersistentFlags().Float64Var(&fontRatio, "font-ratio", 2, "Set height to width ratio of terminal font\nUsed to keep aspect ratio of ascii art\ne.g. --font-ratio 1.6\n(Defaults to 2.0)\n")
+	rootCmd.PersistentFlags().BoolVar(&noAspectCorrection, "no-aspect-correction", false, "Don't correct aspect ratio for terminal fonts\nso each character matches a square of the image\nFor output rendered with square cells\n(Overrides --font-ratio)\n")
+	rootCmd.PersistentFlags().StringVar(&fit, "fit", "stretch", "Set how the image fits --dimensions\nEither stretch, contain or cover\ne.g. --fit contain\n(Defaults to stretch)\n")
+	rootCmd.PersistentFlags().StringVar(&padChar, "pad-char", " ", "Set character that pads --fit contain\nto the full --dimensions\ne.g. --pad-char \".\"\n(Defaults to a space)\n")
+	rootCmd.PersistentFlags().IntSliceVar(&padColor, "pad-color", nil, "Set background color of padding\nadded by --fit contain\nPass an RGB value\ne.g. --pad-color 30,30,30\n(Not colored by default)\n")
+	rootCmd.PersistentFlags().StringVar(&border, "border", "", "Draw a frame around ascii art\nEither single, double, rounded, ascii\nor 6 characters for edges and corners\ne.g. --border rounded\n(Can't be used with --tile-rows)\n")
+	rootCmd.PersistentFlags().IntSliceVar(&borderColor, "border-color", nil, "Set color of frame drawn by --border\nPass an RGB value\ne.g. --border-color 255,200,0\n(Not colored by default)\n")
+	rootCmd.PersistentFlags().BoolVar(&center, "center", false, "Center ascii art horizontally in the terminal\n(Ignored when output isn't a terminal)\n")
+	rootCmd.PersistentFlags().BoolVar(&centerVertical, "center-vertical", false, "Center ascii art vertically in the terminal\n(Ignored when output isn't a terminal)\n")
 	rootCmd.PersistentFlags().BoolVarP(&negative, "negative", "n", false, "Display ascii art in negative colors\n")
+	rootCmd.PersistentFlags().BoolVarP(&invert, "invert", "i", false, "Invert character mapping while keeping colors\nDark pixels get dense characters\n(Useful for light terminal backgrounds)\n")
+	rootCmd.PersistentFlags().StringVar(&theme, "theme", "dark", "Set background of terminal art is shown on\nEither dark, light or auto, which queries\nthe terminal for its background color\n\"light\" inverts character mapping like --invert\ne.g. --theme auto\n(Defaults to dark)\n")
 	rootCmd.PersistentFlags().BoolVarP(&flipX, "flipX", "x", false, "Flip ascii art horizontally\n")
 	rootCmd.PersistentFlags().BoolVarP(&flipY, "flipY", "y", false, "Flip ascii art vertically\n")
 	rootCmd.PersistentFlags().StringVarP(&saveImagePath, "save-img", "s", "", "Save ascii art as a .png file\nFormat: <image-name>-ascii-art.png\nImage will be saved in passed path\n(pass . for current directory)\n")
 	rootCmd.PersistentFlags().StringVar(&saveTxtPath, "save-txt", "", "Save ascii art as a .txt file\nFormat: <image-name>-ascii-art.txt\nFile will be saved in passed path\n(pass . for current directory)\n")
+	rootCmd.PersistentFlags().StringVar(&saveJsonPath, "save-json", "", "Save characters and pixel values as a .json file\nFormat: <image-name>-ascii-art.json\nFile will be saved in passed path\n(pass . for current directory)\n")
 	rootCmd.PersistentFlags().StringVar(&saveGifPath, "save-gif", "", "If input is a gif, save it as a .gif file\nFormat: <gif-name>-ascii-art.gif\nGif will be saved in passed path\n(pass . for current directory)\n")
-	rootCmd.PersistentFlags().IntSliceVar(&saveBgColor, "save-bg", nil, "Set background color for --save-img and --save-gif flags\nPass an RGB value\ne.g. --save-bg 255,255,255\n(Defaults to 0,0,0)\n")
+	rootCmd.PersistentFlags().BoolVar(&gifClear, "gif-clear", false, "Clear the screen before each gif frame\ninstead of redrawing over the last one\n")
+	rootCmd.PersistentFlags().BoolVar(&gifLoop, "loop", false, "Play gifs repeatedly until interrupted\nIgnores the gif's own loop count\n")
+	rootCmd.PersistentFlags().IntVar(&loopCount, "loop-count", -1, "Set number of times gifs are played\n0 plays them forever\ne.g. --loop-count 3\n(Defaults to the gif's own loop count)\n")
+	rootCmd.PersistentFlags().Float64Var(&fps, "fps", 0, "Play gifs at a fixed frame rate\ninstead of their own frame delays\ne.g. --fps 10\n")
+	rootCmd.PersistentFlags().StringVar(&saveHtmlPath, "save-html", "", "Save ascii art as a .html file\nFormat: <image-name>-ascii-art.html\nFile will be saved in passed path\n(pass . for current directory)\n")
+	rootCmd.PersistentFlags().StringVar(&htmlFont, "html-font", "monospace", "Set font family for --save-html flag\nMonospace is kept as a fallback\ne.g. --html-font \"Fira Code\"\n(Defaults to monospace)\n")
+	rootCmd.PersistentFlags().StringVar(&saveSvgPath, "save-svg", "", "Save ascii art as a .svg file\nFormat: <image-name>-ascii-art.svg\nFile will be saved in passed path\n(pass . for current directory)\n")
+	rootCmd.PersistentFlags().IntSliceVar(&svgCellSize, "svg-cell", nil, "Set width and height of each character\nfor --save-svg flag\ne.g. --svg-cell 12,24\n(Defaults to 10,20)\n")
+	rootCmd.PersistentFlags().StringVar(&svgFont, "svg-font", "monospace", "Set font family for --save-svg flag\nMonospace is kept as a fallback\ne.g. --svg-font \"Fira Code\"\n(Defaults to monospace)\n")
+	rootCmd.PersistentFlags().IntSliceVar(&saveBgColor, "save-bg", nil, "Set background color for --save-img,\n--save-gif, --save-html and --save-svg flags\nPass an RGB value\ne.g. --save-bg 255,255,255\n(Defaults to 0,0,0)\n")
 	rootCmd.PersistentFlags().StringVar(&fontFile, "font", "", "Set font for --save-img and --save-gif flags\nPass file path to font .ttf file\ne.g. --font ./RobotoMono-Regular.ttf\n(Defaults to Hack-Regular)\n")
 	rootCmd.PersistentFlags().IntSliceVar(&fontColor, "font-color", nil, "Set font color for terminal as well as\n--save-img and --save-gif flags\nPass an RGB value\ne.g. --font-color 0,0,0\n(Defaults to 255,255,255)\n")
+	rootCmd.PersistentFlags().DurationVar(&urlTimeout, "url-timeout", 30*time.Second, "Set time limit for fetching urls\ne.g. --url-timeout 10s\n(Defaults to 30s)\n")
+	rootCmd.PersistentFlags().StringVar(&userAgent, "user-agent", "ascii-image-converter", "Set User-Agent header for fetching urls\ne.g. --user-agent \"Mozilla/5.0\"\n")
+	rootCmd.PersistentFlags().IntVar(&maxDownload, "max-download", 50, "Set largest file size in MB for fetching urls\ne.g. --max-download 100\n(Defaults to 50)\n")
+	rootCmd.PersistentFlags().IntVar(&maxConcurrency, "max-concurrency", 0, "Set largest number of goroutines for converting\ne.g. --max-concurrency 2\n(Defaults to number of CPUs)\n")
 	rootCmd.PersistentFlags().BoolVar(&formatsTrue, "formats", false, "Display supported input formats\n")
 
 	rootCmd.PersistentFlags().BoolP("help", "h", false, "Help for "+rootCmd.Name()+"\n")
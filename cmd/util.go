@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"path"
+	"unicode/utf8"
 
 	"github.com/TheZoraiz/ascii-image-converter/aic_package/winsize"
 )
@@ -29,9 +30,14 @@ func checkInputAndFlags(args []string) bool {
 	gifCount := 0
 	gifPresent := false
 	nonGifPresent := false
+	stdinCount := 0
 	for _, arg := range args {
 		extension := path.Ext(arg)
 
+		if arg == "-" {
+			stdinCount++
+		}
+
 		if extension == ".gif" {
 			gifPresent = true
 			gifCount++
@@ -45,6 +51,11 @@ func checkInputAndFlags(args []string) bool {
 		return true
 	}
 
+	if stdinCount > 1 {
+		fmt.Printf("Error: stdin can only be passed once with -\n\n")
+		return true
+	}
+
 	if gifCount > 1 {
 		fmt.Printf("Error: There are multiple GIFs supplied\nDue to the potential looping nature of GIFs, only one GIF per command is supported\n\n")
 		return true
@@ -66,11 +77,98 @@ func checkInputAndFlags(args []string) bool {
 		return true
 	}
 
-	if customMap != "" && len(customMap) < 2 {
+	if customMap != "" && utf8.RuneCountInString(customMap) < 2 {
 		fmt.Printf("Need at least 2 characters for --map flag\n\n")
 		return true
 	}
 
+	if alphaThreshold < 0 || alphaThreshold > 255 {
+		fmt.Printf("Error: alpha threshold must be between 0 and 255\n\n")
+		return true
+	}
+
+	if utf8.RuneCountInString(transparentChar) != 1 {
+		fmt.Printf("Error: --transparent-char must be a single character\n\n")
+		return true
+	}
+
+	if tileRows < 0 {
+		fmt.Printf("Error: tile rows can't be negative\n\n")
+		return true
+	}
+
+	if tileRows > 0 && (saveTxtPath != "" || saveJsonPath != "" || saveImagePath != "" || saveHtmlPath != "" || saveSvgPath != "") {
+		fmt.Printf("Error: --tile-rows can't be used with --save-txt, --save-json, --save-img, --save-html or --save-svg\n\n")
+		return true
+	}
+
+	if gradient != nil {
+		if len(gradient) != 6 {
+			fmt.Printf("Error: --gradient requires red, green and blue values of two colors, got %v values\n\n", len(gradient))
+			return true
+		}
+
+		for _, value := range gradient {
+			if value < 0 || value > 255 {
+				fmt.Printf("Error: gradient color values must be between 0 and 255\n\n")
+				return true
+			}
+		}
+	}
+
+	if checkerColors != nil {
+		if len(checkerColors) != 6 {
+			fmt.Printf("Error: --checker-colors requires red, green and blue values of two colors, got %v values\n\n", len(checkerColors))
+			return true
+		}
+
+		for _, value := range checkerColors {
+			if value < 0 || value > 255 {
+				fmt.Printf("Error: checker color values must be between 0 and 255\n\n")
+				return true
+			}
+		}
+	}
+
+	if checkerSize < 1 {
+		fmt.Printf("Error: --checker-size must be at least 1\n\n")
+		return true
+	}
+
+	switch rainbow {
+	case "", "column", "row", "brightness":
+	default:
+		fmt.Printf("Error: --rainbow must be either column, row or brightness\n\n")
+		return true
+	}
+
+	if blur < 0 {
+		fmt.Printf("Error: blur must be greater than 0\n\n")
+		return true
+	}
+
+	if sharpen < 0 {
+		fmt.Printf("Error: sharpen must be greater than 0\n\n")
+		return true
+	}
+
+	if trimTolerance < 0 || trimTolerance > 255 {
+		fmt.Printf("Error: trim tolerance must be between 0 and 255\n\n")
+		return true
+	}
+
+	if crop != nil {
+		if len(crop) != 4 {
+			fmt.Printf("Error: --crop requires x, y, width and height, got %v values\n\n", len(crop))
+			return true
+		}
+
+		if crop[0] < 0 || crop[1] < 0 || crop[2] < 1 || crop[3] < 1 {
+			fmt.Printf("Error: invalid values for crop\n\n")
+			return true
+		}
+	}
+
 	if dimensions != nil {
 
 		numberOfDimensions := len(dimensions)
@@ -84,15 +182,15 @@ func checkInputAndFlags(args []string) bool {
 			return true
 		}
 
+		// Without a terminal, such as when output is redirected, set width isn't limited
 		defaultTermWidth, _, err := winsize.GetTerminalSize()
-		if err != nil {
-			fmt.Printf("Error: %v\n\n", err)
-			return true
+		if err != nil || defaultTermWidth < 1 {
+			defaultTermWidth = dimensions[0] + 1
 		}
 
 		defaultTermWidth -= 1
 		if dimensions[0] > defaultTermWidth {
-			fmt.Printf("Error: set width must be lower than terminal width\n\n")
+			fmt.Printf("Error: set width %v must be lower than terminal width %v\n\n", dimensions[0], defaultTermWidth+1)
 			return true
 		}
 	}
@@ -105,15 +203,14 @@ func checkInputAndFlags(args []string) bool {
 		} else {
 
 			defaultTermWidth, _, err := winsize.GetTerminalSize()
-			if err != nil {
-				fmt.Printf("Error: %v\n\n", err)
-				return true
+			if err != nil || defaultTermWidth < 1 {
+				defaultTermWidth = width + 1
 			}
 
 			// Check if set width exceeds terminal
 			defaultTermWidth -= 1
 			if width > defaultTermWidth {
-				fmt.Printf("Error: set width must be lower than terminal width\n\n")
+				fmt.Printf("Error: set width %v must be lower than terminal width %v\n\n", width, defaultTermWidth+1)
 				return true
 			}
 
@@ -151,6 +248,61 @@ func checkInputAndFlags(args []string) bool {
 		}
 	}
 
+	if alphaBgColor == nil {
+		alphaBgColor = []int{0, 0, 0}
+	} else {
+		bgValues := len(alphaBgColor)
+		if bgValues != 3 {
+			fmt.Printf("Error: --alpha-bg requires 3 values for RGB, got %v\n\n", bgValues)
+			return true
+		}
+
+		if alphaBgColor[0] < 0 || alphaBgColor[1] < 0 || alphaBgColor[2] < 0 {
+			fmt.Printf("Error: RBG values must be between 0 and 255\n\n")
+			return true
+		}
+
+		if alphaBgColor[0] > 255 || alphaBgColor[1] > 255 || alphaBgColor[2] > 255 {
+			fmt.Printf("Error: RBG values must be between 0 and 255\n\n")
+			return true
+		}
+	}
+
+	if rotateBgColor == nil {
+		rotateBgColor = []int{0, 0, 0}
+	} else {
+		bgValues := len(rotateBgColor)
+		if bgValues != 3 {
+			fmt.Printf("Error: --rotate-bg requires 3 values for RGB, got %v\n\n", bgValues)
+			return true
+		}
+
+		if rotateBgColor[0] < 0 || rotateBgColor[1] < 0 || rotateBgColor[2] < 0 {
+			fmt.Printf("Error: RBG values must be between 0 and 255\n\n")
+			return true
+		}
+
+		if rotateBgColor[0] > 255 || rotateBgColor[1] > 255 || rotateBgColor[2] > 255 {
+			fmt.Printf("Error: RBG values must be between 0 and 255\n\n")
+			return true
+		}
+	}
+
+	if svgCellSize == nil {
+		svgCellSize = []int{10, 20}
+	} else {
+		svgCellValues := len(svgCellSize)
+		if svgCellValues != 2 {
+			fmt.Printf("Error: --svg-cell requires 2 values for width and height, got %v\n\n", svgCellValues)
+			return true
+		}
+
+		if svgCellSize[0] < 1 || svgCellSize[1] < 1 {
+			fmt.Printf("Error: invalid values for --svg-cell\n\n")
+			return true
+		}
+	}
+
 	if fontColor == nil {
 		fontColor = []int{255, 255, 255}
 	} else {
@@ -180,5 +332,236 @@ func checkInputAndFlags(args []string) bool {
 		return true
 	}
 
+	if brailleDots != 6 && brailleDots != 8 {
+		fmt.Printf("Error: --braille-dots must be either 6 or 8\n\n")
+		return true
+	}
+
+	if edgeMode != "" && edgeMode != "sobel" && edgeMode != "canny" {
+		fmt.Printf("Error: --edges must be either sobel or canny\n\n")
+		return true
+	}
+
+	if edgeThreshold < 0 || edgeThreshold > 255 {
+		fmt.Printf("Error: edge threshold must be between 0 and 255\n\n")
+		return true
+	}
+
+	if edgeLowThreshold < 0 || edgeLowThreshold > edgeThreshold {
+		fmt.Printf("Error: low edge threshold must be between 0 and --edge-threshold\n\n")
+		return true
+	}
+
+	if edgeBlur <= 0 {
+		fmt.Printf("Error: edge blur must be greater than 0\n\n")
+		return true
+	}
+
+	if graphics != "" {
+		if graphics != "sixel" && graphics != "kitty" && graphics != "iterm" && graphics != "auto" {
+			fmt.Printf("Error: --graphics must be either sixel, kitty, iterm or auto\n\n")
+			return true
+		}
+
+		if gifPresent && graphics != "auto" {
+			fmt.Printf("Error: --graphics flag doesn't work for GIFs\n\n")
+			return true
+		}
+	}
+
+	if colorMode != "truecolor" && colorMode != "256" && colorMode != "16" && colorMode != "8" && colorMode != "plain" && colorMode != "auto" {
+		fmt.Printf("Error: --color-mode must be either truecolor, 256, 16, 8, plain or auto\n\n")
+		return true
+	}
+
+	if theme != "dark" && theme != "light" && theme != "auto" {
+		fmt.Printf("Error: --theme must be either dark, light or auto\n\n")
+		return true
+	}
+
+	if colorDistance != "ciede2000" && colorDistance != "cie76" && colorDistance != "redmean" {
+		fmt.Printf("Error: --color-distance must be either ciede2000, cie76 or redmean\n\n")
+		return true
+	}
+
+	if luminanceWeights != nil {
+		if len(luminanceWeights) != 3 {
+			fmt.Printf("Error: --luminance-weights requires red, green and blue weights, got %v values\n\n", len(luminanceWeights))
+			return true
+		}
+
+		w := luminanceWeights
+		if w[0] < 0 || w[1] < 0 || w[2] < 0 || w[0]+w[1]+w[2] == 0 {
+			fmt.Printf("Error: luminance weights can't be negative or all 0\n\n")
+			return true
+		}
+	}
+
+	switch luminance {
+	case "", "rec601", "rec709", "average":
+	default:
+		fmt.Printf("Error: --luminance must be either rec601, rec709 or average\n\n")
+		return true
+	}
+
+	switch resizeFilter {
+	case "nearest", "box", "linear", "catmull-rom", "lanczos":
+	default:
+		fmt.Printf("Error: --resize-filter must be either nearest, box, linear, catmull-rom or lanczos\n\n")
+		return true
+	}
+
+	if fontRatio <= 0 {
+		fmt.Printf("Error: font ratio must be greater than 0\n\n")
+		return true
+	}
+
+	switch fit {
+	case "stretch", "contain", "cover":
+	default:
+		fmt.Printf("Error: --fit must be either stretch, contain or cover\n\n")
+		return true
+	}
+
+	if utf8.RuneCountInString(padChar) != 1 {
+		fmt.Printf("Error: --pad-char must be a single character\n\n")
+		return true
+	}
+
+	if border != "" {
+		switch border {
+		case "single", "double", "rounded", "ascii":
+		default:
+			if utf8.RuneCountInString(border) != 6 {
+				fmt.Printf("Error: --border must be either single, double, rounded, ascii or 6 characters\n\n")
+				return true
+			}
+		}
+		if tileRows > 0 {
+			fmt.Printf("Error: --border can't be used with --tile-rows\n\n")
+			return true
+		}
+	}
+
+	if borderColor != nil {
+		if len(borderColor) != 3 {
+			fmt.Printf("Error: --border-color requires red, green and blue values, got %v values\n\n", len(borderColor))
+			return true
+		}
+		for _, value := range borderColor {
+			if value < 0 || value > 255 {
+				fmt.Printf("Error: border color values must be between 0 and 255\n\n")
+				return true
+			}
+		}
+	}
+
+	if padColor != nil {
+		if len(padColor) != 3 {
+			fmt.Printf("Error: --pad-color requires red, green and blue values, got %v values\n\n", len(padColor))
+			return true
+		}
+		for _, value := range padColor {
+			if value < 0 || value > 255 {
+				fmt.Printf("Error: pad color values must be between 0 and 255\n\n")
+				return true
+			}
+		}
+	}
+
+	if fps < 0 {
+		fmt.Printf("Error: fps can't be negative\n\n")
+		return true
+	}
+
+	if urlTimeout <= 0 {
+		fmt.Printf("Error: url timeout must be greater than 0\n\n")
+		return true
+	}
+
+	if maxDownload <= 0 {
+		fmt.Printf("Error: max download size must be greater than 0\n\n")
+		return true
+	}
+
+	if maxConcurrency < 0 {
+		fmt.Printf("Error: max concurrency can't be negative\n\n")
+		return true
+	}
+
+	if gamma <= 0 {
+		fmt.Printf("Error: gamma must be greater than 0\n\n")
+		return true
+	}
+
+	if brightness < -100 || brightness > 100 {
+		fmt.Printf("Error: brightness must be between -100 and 100\n\n")
+		return true
+	}
+
+	if contrast <= 0 {
+		fmt.Printf("Error: contrast must be greater than 0\n\n")
+		return true
+	}
+
+	if saturation <= 0 {
+		fmt.Printf("Error: saturation must be greater than 0\n\n")
+		return true
+	}
+
+	if sepia < 0 || sepia > 100 {
+		fmt.Printf("Error: sepia must be between 0 and 100\n\n")
+		return true
+	}
+
+	if quantize != 0 && (quantize < 2 || quantize > 256) {
+		fmt.Printf("Error: quantize colors must be between 2 and 256\n\n")
+		return true
+	}
+
+	if quantizeMethod != "median-cut" && quantizeMethod != "k-means" {
+		fmt.Printf("Error: --quantize-method must be either median-cut or k-means\n\n")
+		return true
+	}
+
+	if colorPalette != "" && colorPalette != "cga" && colorPalette != "gameboy" && colorPalette != "c64" && colorPalette != "nes" {
+		fmt.Printf("Error: --palette must be either cga, gameboy, c64 or nes\n\n")
+		return true
+	}
+
+	if customPalette != nil {
+		if len(customPalette) == 0 || len(customPalette)%3 != 0 {
+			fmt.Printf("Error: --custom-palette requires red, green and blue values of each color, got %v values\n\n", len(customPalette))
+			return true
+		}
+
+		for _, value := range customPalette {
+			if value < 0 || value > 255 {
+				fmt.Printf("Error: custom palette color values must be between 0 and 255\n\n")
+				return true
+			}
+		}
+	}
+
+	if posterize != 0 && (posterize < 2 || posterize > 256) {
+		fmt.Printf("Error: posterize levels must be between 2 and 256\n\n")
+		return true
+	}
+
+	if dithering < 0 || dithering > 1 {
+		fmt.Printf("Error: dithering strength must be between 0.0 and 1.0\n\n")
+		return true
+	}
+
+	if ditherMode != "" && ditherMode != "floyd-steinberg" && ditherMode != "atkinson" && ditherMode != "bayer" {
+		fmt.Printf("Error: --dither-mode must be either floyd-steinberg, atkinson or bayer\n\n")
+		return true
+	}
+
+	if bayerSize != 2 && bayerSize != 4 && bayerSize != 8 {
+		fmt.Printf("Error: --bayer-size must be either 2, 4 or 8\n\n")
+		return true
+	}
+
 	return false
 }
@@ -0,0 +1,313 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"fmt"
+	"math"
+)
+
+// Returns an error for tone adjustments that can't be applied
+func checkAdjustOptions(opts PixelOptions) error {
+	if opts.Gamma < 0 {
+		return fmt.Errorf("gamma must be greater than 0")
+	}
+	if opts.Brightness < -100 || opts.Brightness > 100 {
+		return fmt.Errorf("brightness must be between -100 and 100")
+	}
+	if opts.Contrast < 0 {
+		return fmt.Errorf("contrast must be greater than 0")
+	}
+	if opts.Saturation < 0 {
+		return fmt.Errorf("saturation must be greater than 0")
+	}
+	if opts.Sepia < 0 || opts.Sepia > 100 {
+		return fmt.Errorf("sepia must be between 0 and 100")
+	}
+	if opts.Posterize != 0 && (opts.Posterize < 2 || opts.Posterize > 256) {
+		return fmt.Errorf("posterize levels must be between 2 and 256")
+	}
+	return nil
+}
+
+// Reports whether opts change any values of the resized image
+func hasAdjustments(opts PixelOptions) bool {
+	return (opts.Gamma != 0 && opts.Gamma != 1) || opts.Brightness != 0 || (opts.Contrast != 0 && opts.Contrast != 1) ||
+		(opts.Saturation != 0 && opts.Saturation != 1) || opts.Sepia != 0
+}
+
+/*
+Builds a lookup table mapping each 0-255 value to its adjusted value, so the adjustments only need
+to be calculated once per level instead of once per pixel. Adjustments are applied in this order:
+
+ 1. Gamma correction as out = 255 * (in/255)^(1/gamma), where 0 is treated the same as 1
+ 2. Brightness, which shifts values by brightness percent of 255
+ 3. Contrast, which scales the distance of values from the midpoint 128, where 0 is treated the same as 1
+
+Values are clamped to 0-255 after each step.
+*/
+func buildToneCurve(gamma, brightness, contrast float64) [256]uint32 {
+	var curve [256]uint32
+
+	if gamma == 0 {
+		gamma = 1
+	}
+	if contrast == 0 {
+		contrast = 1
+	}
+
+	for i := range curve {
+		value := MAX_VAL * math.Pow(float64(i)/MAX_VAL, 1/gamma)
+		value = clampFloat(value, 0, MAX_VAL)
+
+		value = clampFloat(value+brightness/100*MAX_VAL, 0, MAX_VAL)
+
+		value = clampFloat((value-128)*contrast+128, 0, MAX_VAL)
+
+		curve[i] = uint32(math.Round(value))
+	}
+
+	return curve
+}
+
+/*
+Applies tone adjustments in opts to each AsciiPixel in imgSet. The charDepth and grayscale values go through every
+adjustment, while RGB values skip gamma correction so colors only track brightness and contrast changes.
+Saturation is then scaled on RGB values alone, followed by the sepia tone.
+*/
+func adjustImgSet(imgSet [][]AsciiPixel, opts PixelOptions) {
+
+	grayCurve := buildToneCurve(opts.Gamma, opts.Brightness, opts.Contrast)
+	rgbCurve := buildToneCurve(1, opts.Brightness, opts.Contrast)
+
+	// Only the 8-bit charDepth goes through the curve, so the 16-bit depth is dropped unless the curve leaves it as is
+	toneChanged := grayCurve != buildToneCurve(1, 0, 1)
+
+	saturation := opts.Saturation
+	if saturation == 0 {
+		saturation = 1
+	}
+
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			pixel := &imgSet[y][x]
+
+			pixel.charDepth = grayCurve[pixel.charDepth]
+			if toneChanged {
+				pixel.hasDepth16 = false
+			}
+			for c := 0; c < 3; c++ {
+				pixel.grayscaleValue[c] = grayCurve[pixel.grayscaleValue[c]]
+				pixel.rgbValue[c] = rgbCurve[pixel.rgbValue[c]]
+			}
+
+			if saturation != 1 {
+				pixel.rgbValue = scaleSaturation(pixel.rgbValue, saturation)
+			}
+			if opts.Sepia != 0 {
+				pixel.rgbValue = sepiaTone(pixel.rgbValue, opts.Sepia/100)
+			}
+		}
+	}
+}
+
+// Quantizes the charDepth, grayscale and RGB values of each AsciiPixel in imgSet to the nearest of passed number of
+// evenly spaced levels, as round(value/step)*step
+func posterizeImgSet(imgSet [][]AsciiPixel, levels int) {
+
+	step := MAX_VAL / float64(levels-1)
+
+	var curve [256]uint32
+	for i := range curve {
+		curve[i] = uint32(math.Round(math.Round(float64(i)/step) * step))
+	}
+
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			pixel := &imgSet[y][x]
+
+			pixel.charDepth = curve[pixel.charDepth]
+			pixel.hasDepth16 = false
+			for c := 0; c < 3; c++ {
+				pixel.grayscaleValue[c] = curve[pixel.grayscaleValue[c]]
+				pixel.rgbValue[c] = curve[pixel.rgbValue[c]]
+			}
+		}
+	}
+}
+
+// Inverts the charDepth, grayscale and RGB values of each AsciiPixel in imgSet
+func invertImgSet(imgSet [][]AsciiPixel) {
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			pixel := &imgSet[y][x]
+
+			pixel.charDepth = 255 - pixel.charDepth
+			if pixel.hasDepth16 {
+				pixel.depth16 = 0xffff - pixel.depth16
+			}
+			for c := 0; c < 3; c++ {
+				pixel.grayscaleValue[c] = 255 - pixel.grayscaleValue[c]
+				pixel.rgbValue[c] = 255 - pixel.rgbValue[c]
+			}
+		}
+	}
+}
+
+/*
+Remaps the charDepth and grayscale values of imgSet through the cumulative distribution of their 256-bin histogram,
+so values are spread evenly across 0-255 instead of crowding a few levels. The darkest value present maps to 0 and
+the brightest to 255. Images with a single value are left unchanged.
+*/
+func equalizeImgSet(imgSet [][]AsciiPixel) {
+
+	var histogram [256]int
+	total := 0
+
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			histogram[imgSet[y][x].charDepth]++
+			total++
+		}
+	}
+
+	var cdf [256]int
+	cdfMin := 0
+	sum := 0
+	for i, count := range histogram {
+		sum += count
+		cdf[i] = sum
+		if cdfMin == 0 {
+			cdfMin = sum
+		}
+	}
+
+	if total == cdfMin {
+		return
+	}
+
+	var curve [256]uint32
+	for i := range curve {
+		if cdf[i] < cdfMin {
+			continue
+		}
+		curve[i] = uint32(math.Round(float64(cdf[i]-cdfMin) / float64(total-cdfMin) * MAX_VAL))
+	}
+
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			pixel := &imgSet[y][x]
+
+			pixel.charDepth = curve[pixel.charDepth]
+			pixel.hasDepth16 = false
+			for c := 0; c < 3; c++ {
+				pixel.grayscaleValue[c] = curve[pixel.grayscaleValue[c]]
+			}
+		}
+	}
+}
+
+// Rows of the sepia transform matrix, giving red, green and blue from the original red, green and blue
+var sepiaMatrix = [3][3]float64{
+	{0.393, 0.769, 0.189},
+	{0.349, 0.686, 0.168},
+	{0.272, 0.534, 0.131},
+}
+
+// Transforms rgb with sepiaMatrix and blends the original color towards it by intensity, between 0 and 1
+func sepiaTone(rgb [3]uint32, intensity float64) [3]uint32 {
+	var toned [3]uint32
+
+	for c, row := range sepiaMatrix {
+		sepia := row[0]*float64(rgb[0]) + row[1]*float64(rgb[1]) + row[2]*float64(rgb[2])
+		value := float64(rgb[c]) + (clampFloat(sepia, 0, MAX_VAL)-float64(rgb[c]))*intensity
+		toned[c] = uint32(math.Round(value))
+	}
+
+	return toned
+}
+
+// Converts rgb to HSL, multiplies its saturation by factor and converts it back
+func scaleSaturation(rgb [3]uint32, factor float64) [3]uint32 {
+	h, s, l := rgbToHsl(rgb)
+	return hslToRgb(h, clampFloat(s*factor, 0, 1), l)
+}
+
+// Returns hue in degrees, along with saturation and lightness between 0 and 1
+func rgbToHsl(rgb [3]uint32) (float64, float64, float64) {
+	r := float64(rgb[0]) / MAX_VAL
+	g := float64(rgb[1]) / MAX_VAL
+	b := float64(rgb[2]) / MAX_VAL
+
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l := (max + min) / 2
+
+	// Achromatic colors have no hue or saturation
+	if max == min {
+		return 0, 0, l
+	}
+
+	delta := max - min
+
+	var s float64
+	if l > 0.5 {
+		s = delta / (2 - max - min)
+	} else {
+		s = delta / (max + min)
+	}
+
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/delta+6, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+
+	return h * 60, s, l
+}
+
+func hslToRgb(h, s, l float64) [3]uint32 {
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - chroma/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	return [3]uint32{
+		uint32(math.Round(clampFloat((r+m)*MAX_VAL, 0, MAX_VAL))),
+		uint32(math.Round(clampFloat((g+m)*MAX_VAL, 0, MAX_VAL))),
+		uint32(math.Round(clampFloat((b+m)*MAX_VAL, 0, MAX_VAL))),
+	}
+}
@@ -17,9 +17,7 @@ limitations under the License.
 package image_conversions
 
 import (
-	"strconv"
-
-	"github.com/gookit/color"
+	"unicode/utf8"
 )
 
 var (
@@ -35,6 +33,8 @@ var (
 		{0x40, 0x80},
 	}
 
+	// Deprecated: ConvertToBrailleChars() no longer sets this, since writing it made concurrent conversions race.
+	// Its threshold parameter is used instead
 	BrailleThreshold uint32
 )
 
@@ -46,6 +46,63 @@ type AsciiChar struct {
 	SetColor      string
 	Simple        string
 	RgbValue      [3]uint32
+
+	// Only set for half-block characters, where the background represents the lower pixel
+	BgRgbValue [3]uint32
+}
+
+// Returns the number of characters that grayscale values will be mapped against by ConvertToAsciiChars()
+func CharacterCount(complex bool, customMap string) int {
+	return len(characterTable(complex, customMap))
+}
+
+// Splits the chosen character set into its characters, ordered from darkest to lightest.
+// Characters are split by rune so multi-byte characters in customMap are kept whole
+func characterTable(complex bool, customMap string) []string {
+	charSet := customMap
+	if charSet == "" {
+		if complex {
+			charSet = asciiTableDetailed
+		} else {
+			charSet = asciiTableSimple
+		}
+	}
+
+	table := make([]string, 0, utf8.RuneCountInString(charSet))
+	for _, char := range charSet {
+		table = append(table, string(char))
+	}
+
+	return table
+}
+
+/*
+Returns the character of ramp that ConvertToAsciiChars() maps a grayscale depth from 0 to 255 to. ramp is ordered
+from darkest to lightest and split evenly into buckets, where a depth at a bucket's lower boundary belongs to it and
+255 always maps to the last character. If invert is set, the character from the opposite end of ramp is returned.
+Depths above 255 are treated as 255, and 0 is returned for an empty ramp.
+*/
+func CharForDepth(depth uint32, ramp []rune, invert bool) rune {
+	if len(ramp) == 0 {
+		return 0
+	}
+	if depth > uint32(MAX_VAL) {
+		depth = uint32(MAX_VAL)
+	}
+
+	index := depthIndex(float64(depth), MAX_VAL, len(ramp))
+	if invert {
+		index = len(ramp) - 1 - index
+	}
+	return ramp[index]
+}
+
+// Gets appropriate index out of count characters for value by percentage comparisons with maxValue
+func depthIndex(value, maxValue float64, count int) int {
+	if value >= maxValue {
+		return count - 1
+	}
+	return int((value / maxValue) * float64(count))
 }
 
 /*
@@ -53,52 +110,49 @@ Converts the 2D image_conversions.AsciiPixel slice of image data (each instance
 to a 2D image_conversions.AsciiChar slice
 
 If complex parameter is true, values are compared to 70 levels of color density in ASCII characters.
-Otherwise, values are compared to 10 levels of color density in ASCII characters.
+Otherwise, values are compared to 10 levels of color density in ASCII characters. If customMap is passed,
+values are compared to as many levels as it has characters (runes, not bytes), overriding complex.
 */
 func ConvertToAsciiChars(imgSet [][]AsciiPixel, negative, colored, complex, colorBg bool, customMap string, fontColor [3]int) [][]AsciiChar {
+	return ConvertToAsciiCharsWithOptions(imgSet, negative, colored, complex, colorBg, customMap, fontColor, CharOptions{})
+}
 
-	height := len(imgSet)
-	width := len(imgSet[0])
-
-	chosenTable := map[int]string{}
+// Same as ConvertToAsciiChars(), except that opts are applied as well
+func ConvertToAsciiCharsWithOptions(imgSet [][]AsciiPixel, negative, colored, complex, colorBg bool, customMap string, fontColor [3]int, opts CharOptions) [][]AsciiChar {
 
-	// Turn ascii character-set string into map[int]string{} literal
-	if customMap == "" {
-		var charSet string
+	opts = resolveColorMode(opts)
 
-		if complex {
-			charSet = asciiTableDetailed
-		} else {
-			charSet = asciiTableSimple
-		}
-
-		for index, char := range charSet {
-			chosenTable[index] = string(char)
-		}
+	height := len(imgSet)
+	width := len(imgSet[0])
 
-	} else {
-		chosenTable = map[int]string{}
+	chosenTable := characterTable(complex, customMap)
 
-		for index, char := range customMap {
-			chosenTable[index] = string(char)
+	if opts.ReverseMap {
+		for i, j := 0, len(chosenTable)-1; i < j; i, j = i+1, j-1 {
+			chosenTable[i], chosenTable[j] = chosenTable[j], chosenTable[i]
 		}
 	}
 
-	var result [][]AsciiChar
+	result := make([][]AsciiChar, 0, height)
 
 	for i := 0; i < height; i++ {
 
-		var tempSlice []AsciiChar
+		tempSlice := make([]AsciiChar, 0, width)
 
 		for j := 0; j < width; j++ {
-			value := float64(imgSet[i][j].charDepth)
+			if isTransparent(imgSet[i][j], opts) {
+				tempSlice = append(tempSlice, transparentChar(opts))
+				continue
+			}
 
-			// Gets appropriate string index from chosenTable by percentage comparisons with its length
-			tempFloat := (value / MAX_VAL) * float64(len(chosenTable))
-			if value == MAX_VAL {
-				tempFloat = float64(len(chosenTable) - 1)
+			// Matched characters are chosen from the pixel before negative inverts it
+			var matched string
+			if opts.Matcher != nil {
+				matched = string(opts.Matcher.Match(imgSet[i][j]))
 			}
-			tempInt := int(tempFloat)
+
+			value, maxValue := depthValue(imgSet[i][j])
+			tempInt := depthIndex(value, maxValue, len(chosenTable))
 
 			var r, g, b int
 
@@ -128,30 +182,23 @@ func ConvertToAsciiChars(imgSet [][]AsciiPixel, negative, colored, complex, colo
 				tempInt = (len(chosenTable) - 1) - tempInt
 			}
 
-			rStr := strconv.Itoa(r)
-			gStr := strconv.Itoa(g)
-			bStr := strconv.Itoa(b)
+			if opts.Invert {
+				tempInt = (len(chosenTable) - 1) - tempInt
+			}
+
+			simple := chosenTable[tempInt]
+			if opts.Matcher != nil {
+				simple = matched
+			}
 
 			var char AsciiChar
 
-			char.Simple = chosenTable[tempInt]
-			if colorBg {
-				char.OriginalColor = color.Sprintf("<bg="+rStr+","+gStr+","+bStr+">%v</>", chosenTable[tempInt])
-			} else {
-				char.OriginalColor = color.Sprintf("<fg="+rStr+","+gStr+","+bStr+">%v</>", chosenTable[tempInt])
-			}
+			char.Simple = simple
+			char.OriginalColor = pixelColorText(simple, [3]int{r, g, b}, colorBg, opts)
 
-			// If font color is not set, use a simple string. Otherwise, use True color
+			// If font color is not set, use a simple string. Otherwise, use set color mode
 			if fontColor != [3]int{255, 255, 255} {
-				fcR := strconv.Itoa(fontColor[0])
-				fcG := strconv.Itoa(fontColor[1])
-				fcB := strconv.Itoa(fontColor[2])
-
-				if colorBg {
-					char.SetColor = color.Sprintf("<bg="+fcR+","+fcG+","+fcB+">%v</>", chosenTable[tempInt])
-				} else {
-					char.SetColor = color.Sprintf("<fg="+fcR+","+fcG+","+fcB+">%v</>", chosenTable[tempInt])
-				}
+				char.SetColor = colorText(simple, fontColor, colorBg, opts)
 			}
 
 			if colored {
@@ -175,80 +222,47 @@ to a 2D image_conversions.AsciiChar slice
 Unlike ConvertToAsciiChars(), this function calculates braille characters instead of ascii
 */
 func ConvertToBrailleChars(imgSet [][]AsciiPixel, negative, colored, colorBg bool, fontColor [3]int, threshold int) [][]AsciiChar {
+	return ConvertToBrailleCharsWithOptions(imgSet, negative, colored, colorBg, fontColor, threshold, CharOptions{})
+}
+
+/*
+Same as ConvertToBrailleChars(), except that opts are applied as well. With opts.BrailleDots set to 6, each character
+covers 2x3 pixels and only its upper 6 dots are used.
+*/
+func ConvertToBrailleCharsWithOptions(imgSet [][]AsciiPixel, negative, colored, colorBg bool, fontColor [3]int, threshold int, opts CharOptions) [][]AsciiChar {
 
-	BrailleThreshold = uint32(threshold)
+	opts = resolveColorMode(opts)
 
 	height := len(imgSet)
 	width := len(imgSet[0])
+	rows := brailleRows(opts.BrailleDots)
 
-	var result [][]AsciiChar
+	result := make([][]AsciiChar, 0, height/rows)
+	cell := make([]AsciiPixel, 0, 8)
 
-	for i := 0; i < height; i += 4 {
+	for i := 0; i+rows <= height; i += rows {
 
-		var tempSlice []AsciiChar
-
-		for j := 0; j < width; j += 2 {
-
-			brailleChar := getBrailleChar(i, j, negative, imgSet)
-
-			var r, g, b int
-
-			if colored {
-				r = int(imgSet[i][j].rgbValue[0])
-				g = int(imgSet[i][j].rgbValue[1])
-				b = int(imgSet[i][j].rgbValue[2])
-			} else {
-				r = int(imgSet[i][j].grayscaleValue[0])
-				g = int(imgSet[i][j].grayscaleValue[1])
-				b = int(imgSet[i][j].grayscaleValue[2])
-			}
+		tempSlice := make([]AsciiChar, 0, width/2)
 
-			if negative {
-				// Select character from opposite side of table as well as turn pixels negative
-				r = 255 - r
-				g = 255 - g
-				b = 255 - b
+		for j := 0; j+1 < width; j += 2 {
 
-				if colored {
-					imgSet[i][j].rgbValue = [3]uint32{uint32(r), uint32(g), uint32(b)}
-				} else {
-					imgSet[i][j].grayscaleValue = [3]uint32{uint32(r), uint32(g), uint32(b)}
-				}
+			cell = cell[:0]
+			for y := i; y < i+rows; y++ {
+				cell = append(cell, imgSet[y][j], imgSet[y][j+1])
 			}
 
-			rStr := strconv.Itoa(r)
-			gStr := strconv.Itoa(g)
-			bStr := strconv.Itoa(b)
-
-			var char AsciiChar
-
-			char.Simple = brailleChar
-			if colorBg {
-				char.OriginalColor = color.Sprintf("<bg="+rStr+","+gStr+","+bStr+">%v</>", brailleChar)
-			} else {
-				char.OriginalColor = color.Sprintf("<fg="+rStr+","+gStr+","+bStr+">%v</>", brailleChar)
+			if allTransparent(cell, opts) {
+				tempSlice = append(tempSlice, transparentChar(opts))
+				continue
 			}
 
-			// If font color is not set, use a simple string. Otherwise, use True color
-			if fontColor != [3]int{255, 255, 255} {
-				fcR := strconv.Itoa(fontColor[0])
-				fcG := strconv.Itoa(fontColor[1])
-				fcB := strconv.Itoa(fontColor[2])
+			// Inverting dots twice leaves them as they were
+			dots := getBrailleDots(i, j, rows, negative != opts.Invert, uint32(threshold), imgSet, opts)
+			brailleChar := string(rune(0x2800 + dots))
 
-				if colorBg {
-					char.SetColor = color.Sprintf("<bg="+fcR+","+fcG+","+fcB+">%v</>", brailleChar)
-				} else {
-					char.SetColor = color.Sprintf("<fg="+fcR+","+fcG+","+fcB+">%v</>", brailleChar)
-				}
-			}
-
-			if colored {
-				char.RgbValue = imgSet[i][j].rgbValue
-			} else {
-				char.RgbValue = imgSet[i][j].grayscaleValue
-			}
+			pixel := brailleColorPixel(i, j, rows, dots, imgSet, opts)
 
-			tempSlice = append(tempSlice, char)
+			tempSlice = append(tempSlice, coloredChar(brailleChar, pixel, negative, colored, colorBg, fontColor, opts))
 		}
 
 		result = append(result, tempSlice)
@@ -257,24 +271,90 @@ func ConvertToBrailleChars(imgSet [][]AsciiPixel, negative, colored, colorBg boo
 	return result
 }
 
-// Iterate through the BrailleStruct table to see which dots need to be highlighted
-func getBrailleChar(x, y int, negative bool, imgSet [][]AsciiPixel) string {
+// Iterate through the first rows of the BrailleStruct table to see which dots need to be highlighted. Transparent pixels are
+// never highlighted. Returned value is the offset of the braille character from U+2800
+func getBrailleDots(x, y, rows int, negative bool, threshold uint32, imgSet [][]AsciiPixel, opts CharOptions) int {
 
-	brailleChar := 0x2800
+	dots := 0
 
-	for i := 0; i < 4; i++ {
+	for i := 0; i < rows; i++ {
 		for j := 0; j < 2; j++ {
+			if isTransparent(imgSet[x+i][y+j], opts) {
+				continue
+			}
+
 			if negative {
-				if imgSet[x+i][y+j].charDepth <= BrailleThreshold {
-					brailleChar += BrailleStruct[i][j]
+				if imgSet[x+i][y+j].charDepth <= threshold {
+					dots |= BrailleStruct[i][j]
 				}
 			} else {
-				if imgSet[x+i][y+j].charDepth >= BrailleThreshold {
-					brailleChar += BrailleStruct[i][j]
+				if imgSet[x+i][y+j].charDepth >= threshold {
+					dots |= BrailleStruct[i][j]
 				}
 			}
 		}
 	}
 
-	return string(brailleChar)
+	return dots
+}
+
+/*
+Returns a pixel holding the average RGB and grayscale values of the highlighted dots of the braille character at x, y,
+so its color represents what's actually drawn instead of a single pixel. If no dots are highlighted, all of its
+non-transparent pixels are averaged instead, which matters when the color is used as a background.
+*/
+func brailleColorPixel(x, y, rows, dots int, imgSet [][]AsciiPixel, opts CharOptions) AsciiPixel {
+
+	var highlighted, visible []AsciiPixel
+
+	for i := 0; i < rows; i++ {
+		for j := 0; j < 2; j++ {
+			pixel := imgSet[x+i][y+j]
+			if isTransparent(pixel, opts) {
+				continue
+			}
+
+			visible = append(visible, pixel)
+			if dots&BrailleStruct[i][j] != 0 {
+				highlighted = append(highlighted, pixel)
+			}
+		}
+	}
+
+	if len(highlighted) > 0 {
+		return averagePixel(highlighted)
+	}
+	return averagePixel(visible)
+}
+
+// Returns the rows of dots in braille characters with passed number of dots, which is 4 unless it's 6
+func brailleRows(dots int) int {
+	if dots == 6 {
+		return 3
+	}
+	return 4
+}
+
+// Returns a pixel with the rounded average RGB and grayscale values of passed pixels
+func averagePixel(pixels []AsciiPixel) AsciiPixel {
+
+	if len(pixels) == 0 {
+		return AsciiPixel{}
+	}
+
+	var rgb, gray [3]uint32
+	for _, pixel := range pixels {
+		for c := 0; c < 3; c++ {
+			rgb[c] += pixel.rgbValue[c]
+			gray[c] += pixel.grayscaleValue[c]
+		}
+	}
+
+	n := uint32(len(pixels))
+	for c := 0; c < 3; c++ {
+		rgb[c] = (rgb[c] + n/2) / n
+		gray[c] = (gray[c] + n/2) / n
+	}
+
+	return AsciiPixel{rgbValue: rgb, grayscaleValue: gray}
 }
@@ -0,0 +1,88 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import "testing"
+
+func TestCharForDepth(t *testing.T) {
+	simple := []rune(asciiTableSimple)
+	blocks := []rune("░▒▓█")
+
+	tests := []struct {
+		name   string
+		depth  uint32
+		ramp   []rune
+		invert bool
+		want   rune
+	}{
+		{"zero", 0, simple, false, ' '},
+		{"below first boundary", 25, simple, false, ' '},
+		{"first boundary", 26, simple, false, '.'},
+		{"below middle boundary", 127, simple, false, '='},
+		{"middle boundary", 128, simple, false, '+'},
+		{"last boundary", 230, simple, false, '@'},
+		{"max", 255, simple, false, '@'},
+		{"above max", 256, simple, false, '@'},
+		{"far above max", 65535, simple, false, '@'},
+		{"empty ramp", 128, nil, false, 0},
+		{"empty ramp inverted", 128, []rune{}, true, 0},
+		{"single rune", 0, []rune{'#'}, false, '#'},
+		{"single rune max", 255, []rune{'#'}, false, '#'},
+		{"multi-byte first bucket", 63, blocks, false, '░'},
+		{"multi-byte second boundary", 64, blocks, false, '▒'},
+		{"multi-byte third boundary", 128, blocks, false, '▓'},
+		{"multi-byte below last boundary", 191, blocks, false, '▓'},
+		{"multi-byte last boundary", 192, blocks, false, '█'},
+		{"multi-byte max", 255, blocks, false, '█'},
+		{"invert zero", 0, simple, true, '@'},
+		{"invert first boundary", 26, simple, true, '%'},
+		{"invert max", 255, simple, true, ' '},
+		{"invert above max", 1000, simple, true, ' '},
+		{"invert multi-byte", 64, blocks, true, '▓'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CharForDepth(tt.depth, tt.ramp, tt.invert); got != tt.want {
+				t.Errorf("CharForDepth(%d, %q, %v) = %q, want %q", tt.depth, string(tt.ramp), tt.invert, got, tt.want)
+			}
+		})
+	}
+}
+
+// Every depth from 0 to 255 must land in the bucket its lower boundary starts, for ramps of several lengths
+func TestCharForDepthBoundaries(t *testing.T) {
+	for _, length := range []int{1, 2, 4, 10, 70} {
+		ramp := make([]rune, length)
+		for i := range ramp {
+			ramp[i] = rune('a' + i)
+		}
+
+		for index := 0; index < length; index++ {
+			// Lowest depth with depth*length/255 >= index
+			lower := (index*255 + length - 1) / length
+			if got := CharForDepth(uint32(lower), ramp, false); got != ramp[index] {
+				t.Errorf("length %d: depth %d = %q, want %q", length, lower, got, ramp[index])
+			}
+			if index > 0 {
+				if got := CharForDepth(uint32(lower-1), ramp, false); got != ramp[index-1] {
+					t.Errorf("length %d: depth %d = %q, want %q", length, lower-1, got, ramp[index-1])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,314 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+// Upper half block character for ConvertToHalfBlockChars()
+const halfBlockChar = "\u2580"
+
+// Quadrant characters indexed by which of their subpixels are filled.
+// Bits from lowest to highest are upper left, upper right, lower left and lower right
+var quadrantChars = [16]string{
+	" ", "\u2598", "\u259D", "\u2580",
+	"\u2596", "\u258C", "\u259E", "\u259B",
+	"\u2597", "\u259A", "\u2590", "\u259C",
+	"\u2584", "\u2599", "\u259F", "\u2588",
+}
+
+// Sextant characters indexed by which of their subpixels are filled, in the same row by row order as quadrantChars
+var sextantChars = buildSextantChars()
+
+/*
+Sextants from the Symbols for Legacy Computing block are ordered by the same index from U+1FB00, leaving out the
+empty and full blocks, and the left and right halves that already exist as U+258C and U+2590 at indices 21 and 42
+*/
+func buildSextantChars() [64]string {
+	var chars [64]string
+
+	code := rune(0x1FB00)
+	for index := range chars {
+		switch index {
+		case 0:
+			chars[index] = " "
+		case 21:
+			chars[index] = "\u258C"
+		case 42:
+			chars[index] = "\u2590"
+		case 63:
+			chars[index] = "\u2588"
+		default:
+			chars[index] = string(code)
+			code++
+		}
+	}
+
+	return chars
+}
+
+// Octant characters indexed by which of their subpixels are filled, in the same row by row order as quadrantChars
+var octantChars = buildOctantChars()
+
+// Octant patterns left out of the octants from U+1CD00, since they already exist as block or quarter block characters
+var octantExisting = map[int]string{
+	0: " ", 255: "\u2588",
+
+	// Quadrants and halves
+	5: "\u2598", 10: "\u259D", 80: "\u2596", 160: "\u2597",
+	15: "\u2580", 240: "\u2584", 85: "\u258C", 170: "\u2590",
+	165: "\u259A", 90: "\u259E", 95: "\u259B", 175: "\u259C", 245: "\u2599", 250: "\u259F",
+
+	// Quarter blocks
+	3: "\U0001FB82", 63: "\U0001FB85", 192: "\u2582", 252: "\u2586",
+	20: "\U0001FBE6", 40: "\U0001FBE7",
+	1: "\U0001CEA8", 2: "\U0001CEAB", 64: "\U0001CEA3", 128: "\U0001CEA0",
+}
+
+// Octants are ordered by the same index from U+1CD00, leaving out patterns in octantExisting
+func buildOctantChars() [256]string {
+	var chars [256]string
+
+	code := rune(0x1CD00)
+	for index := range chars {
+		if char, ok := octantExisting[index]; ok {
+			chars[index] = char
+			continue
+		}
+		chars[index] = string(code)
+		code++
+	}
+
+	return chars
+}
+
+/*
+Converts the 2D image_conversions.AsciiPixel slice of image data (each instance representing each compressed pixel of original image)
+to a 2D image_conversions.AsciiChar slice
+
+Unlike ConvertToAsciiChars(), each character represents two vertically adjacent pixels. The upper pixel is drawn with the
+foreground color on an upper half block character, while the lower pixel is drawn as its background color. Grayscale
+values are used instead of RGB values if colored is false
+*/
+func ConvertToHalfBlockChars(imgSet [][]AsciiPixel, negative, colored bool, opts CharOptions) [][]AsciiChar {
+
+	opts = resolveColorMode(opts)
+
+	height := len(imgSet)
+	width := len(imgSet[0])
+
+	result := make([][]AsciiChar, 0, (height+1)/2)
+
+	for i := 0; i < height; i += 2 {
+
+		tempSlice := make([]AsciiChar, 0, width)
+
+		for j := 0; j < width; j++ {
+
+			pixels := []AsciiPixel{imgSet[i][j]}
+			if i+1 < height {
+				pixels = append(pixels, imgSet[i+1][j])
+			}
+			if allTransparent(pixels, opts) {
+				tempSlice = append(tempSlice, transparentChar(opts))
+				continue
+			}
+
+			upper := pixelColor(imgSet[i][j], negative, colored)
+
+			// In case of an odd number of rows, the last line's lower half is left black
+			var lower [3]uint32
+			if i+1 < height {
+				lower = pixelColor(imgSet[i+1][j], negative, colored)
+			}
+
+			var char AsciiChar
+
+			char.Simple = halfBlockChar
+			char.OriginalColor = colorTextDual(halfBlockChar, toIntRgb(upper), toIntRgb(lower), opts)
+			char.SetColor = char.OriginalColor
+			char.RgbValue = upper
+			char.BgRgbValue = lower
+
+			tempSlice = append(tempSlice, char)
+		}
+
+		result = append(result, tempSlice)
+	}
+
+	return result
+}
+
+/*
+Converts the 2D image_conversions.AsciiPixel slice of image data (each instance representing each compressed pixel of original image)
+to a 2D image_conversions.AsciiChar slice
+
+Unlike ConvertToAsciiChars(), every character is a space with its pixel drawn as the background color, which makes
+solid colored blocks instead of characters. Grayscale values are used instead of RGB values if colored is false
+*/
+func ConvertToBgBlockChars(imgSet [][]AsciiPixel, negative, colored bool, opts CharOptions) [][]AsciiChar {
+
+	opts = resolveColorMode(opts)
+
+	result := make([][]AsciiChar, 0, len(imgSet))
+
+	for _, row := range imgSet {
+
+		tempSlice := make([]AsciiChar, 0, len(row))
+
+		for _, pixel := range row {
+			if isTransparent(pixel, opts) {
+				tempSlice = append(tempSlice, transparentChar(opts))
+				continue
+			}
+
+			rgb := pixelColor(pixel, negative, colored)
+
+			var char AsciiChar
+
+			char.Simple = " "
+			char.OriginalColor = colorText(" ", toIntRgb(rgb), true, opts)
+			char.SetColor = char.OriginalColor
+
+			// Both halves are set so saved files can fill the cell the same way as half block characters
+			char.RgbValue = rgb
+			char.BgRgbValue = rgb
+
+			tempSlice = append(tempSlice, char)
+		}
+
+		result = append(result, tempSlice)
+	}
+
+	return result
+}
+
+// Returns RGB or grayscale value of passed pixel, inverted if negative is true
+func pixelColor(pixel AsciiPixel, negative, colored bool) [3]uint32 {
+	value := pixel.grayscaleValue
+	if colored {
+		value = pixel.rgbValue
+	}
+
+	if negative {
+		value = [3]uint32{255 - value[0], 255 - value[1], 255 - value[2]}
+	}
+
+	return value
+}
+
+/*
+Converts the 2D image_conversions.AsciiPixel slice of image data (each instance representing each compressed pixel of original image)
+to a 2D image_conversions.AsciiChar slice
+
+Unlike ConvertToAsciiChars(), each character represents a 2x2 block of pixels. Each pixel is compared against threshold to
+decide whether its quadrant is filled, and the matching quadrant block character is selected
+*/
+func ConvertToQuadrantChars(imgSet [][]AsciiPixel, negative, colored, colorBg bool, fontColor [3]int, threshold int, opts CharOptions) [][]AsciiChar {
+	return convertToBlockChars(imgSet, 2, quadrantChars[:], negative, colored, colorBg, fontColor, threshold, opts)
+}
+
+/*
+Converts the 2D image_conversions.AsciiPixel slice of image data (each instance representing each compressed pixel of original image)
+to a 2D image_conversions.AsciiChar slice
+
+Same as ConvertToQuadrantChars(), except that each character represents a 2x3 block of pixels drawn with sextant characters.
+imgSet should be converted with PixelOptions.Sextant set. Sextants need a font with the Symbols for Legacy Computing block
+*/
+func ConvertToSextantChars(imgSet [][]AsciiPixel, negative, colored, colorBg bool, fontColor [3]int, threshold int, opts CharOptions) [][]AsciiChar {
+	return convertToBlockChars(imgSet, 3, sextantChars[:], negative, colored, colorBg, fontColor, threshold, opts)
+}
+
+/*
+Converts the 2D image_conversions.AsciiPixel slice of image data (each instance representing each compressed pixel of original image)
+to a 2D image_conversions.AsciiChar slice
+
+Same as ConvertToQuadrantChars(), except that each character represents a 2x4 block of pixels drawn with octant characters,
+which fill whole blocks unlike braille dots. imgSet should be converted with PixelOptions.Octant set. Octants need a font with
+the Symbols for Legacy Computing Supplement block
+*/
+func ConvertToOctantChars(imgSet [][]AsciiPixel, negative, colored, colorBg bool, fontColor [3]int, threshold int, opts CharOptions) [][]AsciiChar {
+	return convertToBlockChars(imgSet, 4, octantChars[:], negative, colored, colorBg, fontColor, threshold, opts)
+}
+
+// Converts each block of 2 pixels by passed rows to the character of chars indexed by which of its pixels reach threshold
+func convertToBlockChars(imgSet [][]AsciiPixel, rows int, chars []string, negative, colored, colorBg bool, fontColor [3]int, threshold int, opts CharOptions) [][]AsciiChar {
+
+	opts = resolveColorMode(opts)
+
+	height := len(imgSet)
+	width := len(imgSet[0])
+
+	result := make([][]AsciiChar, 0, height/rows)
+	pixels := make([]AsciiPixel, 0, 2*rows)
+
+	for i := 0; i+rows <= height; i += rows {
+
+		tempSlice := make([]AsciiChar, 0, width/2)
+
+		for j := 0; j+1 < width; j += 2 {
+
+			pixels = pixels[:0]
+			for y := i; y < i+rows; y++ {
+				pixels = append(pixels, imgSet[y][j], imgSet[y][j+1])
+			}
+			if allTransparent(pixels, opts) {
+				tempSlice = append(tempSlice, transparentChar(opts))
+				continue
+			}
+
+			index := 0
+			for bit, pixel := range pixels {
+				if isTransparent(pixel, opts) {
+					continue
+				}
+
+				filled := pixel.charDepth >= uint32(threshold)
+				if negative != opts.Invert {
+					filled = pixel.charDepth <= uint32(threshold)
+				}
+
+				if filled {
+					index |= 1 << bit
+				}
+			}
+
+			tempSlice = append(tempSlice, coloredChar(chars[index], imgSet[i][j], negative, colored, colorBg, fontColor, opts))
+		}
+
+		result = append(result, tempSlice)
+	}
+
+	return result
+}
+
+// Returns an AsciiChar of passed character, colored with pixel's RGB or grayscale value or the font color
+func coloredChar(simple string, pixel AsciiPixel, negative, colored, colorBg bool, fontColor [3]int, opts CharOptions) AsciiChar {
+
+	rgb := pixelColor(pixel, negative, colored)
+
+	var char AsciiChar
+
+	char.Simple = simple
+	char.OriginalColor = pixelColorText(simple, toIntRgb(rgb), colorBg, opts)
+
+	// If font color is not set, use a simple string. Otherwise, use set color mode
+	if fontColor != [3]int{255, 255, 255} {
+		char.SetColor = colorText(simple, fontColor, colorBg, opts)
+	}
+
+	char.RgbValue = rgb
+
+	return char
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Box-drawing characters of the border styles selectable by name in BorderChars(), in the order of horizontal and
+// vertical edges followed by the top left, top right, bottom left and bottom right corners
+var borderStyles = map[string]string{
+	"single":  "─│┌┐└┘",
+	"double":  "═║╔╗╚╝",
+	"rounded": "─│╭╮╰╯",
+	"ascii":   "-|++++",
+}
+
+/*
+Returns the characters of border, which is either the name of a style ("single", "double", "rounded" or "ascii")
+or 6 custom characters in the order of horizontal and vertical edges followed by the top left, top right,
+bottom left and bottom right corners, e.g. "=|####"
+*/
+func BorderChars(border string) ([6]string, error) {
+	var chars [6]string
+
+	if style, ok := borderStyles[border]; ok {
+		border = style
+	}
+
+	if utf8.RuneCountInString(border) != 6 {
+		return chars, fmt.Errorf("border must be either single, double, rounded, ascii or 6 characters, got %q", border)
+	}
+
+	i := 0
+	for _, char := range border {
+		chars[i] = string(char)
+		i++
+	}
+
+	return chars, nil
+}
+
+/*
+Surrounds asciiSet with a frame of passed border characters, as returned by BorderChars(). Since asciiSet holds
+characters rather than pixels, the frame fits braille and block characters the same way as ascii characters. If
+borderColor is set, the frame is drawn in it. Otherwise it's left uncolored and white in saved images.
+*/
+func FrameAsciiChars(asciiSet [][]AsciiChar, chars [6]string, borderColor *[3]int, opts CharOptions) [][]AsciiChar {
+
+	opts = resolveColorMode(opts)
+
+	if len(asciiSet) == 0 {
+		return asciiSet
+	}
+
+	frameChar := func(simple string) AsciiChar {
+		char := AsciiChar{Simple: simple, OriginalColor: simple, SetColor: simple, RgbValue: [3]uint32{255, 255, 255}}
+		if borderColor != nil {
+			char.OriginalColor = colorText(simple, *borderColor, false, opts)
+			char.SetColor = char.OriginalColor
+			char.RgbValue = [3]uint32{uint32(borderColor[0]), uint32(borderColor[1]), uint32(borderColor[2])}
+		}
+		char.BgRgbValue = char.RgbValue
+		return char
+	}
+
+	width := len(asciiSet[0])
+
+	edgeRow := func(left, right string) []AsciiChar {
+		row := make([]AsciiChar, width+2)
+		for i := range row {
+			row[i] = frameChar(chars[0])
+		}
+		row[0] = frameChar(left)
+		row[width+1] = frameChar(right)
+		return row
+	}
+
+	result := make([][]AsciiChar, 0, len(asciiSet)+2)
+	result = append(result, edgeRow(chars[2], chars[3]))
+
+	for _, line := range asciiSet {
+		row := make([]AsciiChar, 0, width+2)
+		row = append(row, frameChar(chars[1]))
+		row = append(row, line...)
+		row = append(row, frameChar(chars[1]))
+		result = append(result, row)
+	}
+
+	result = append(result, edgeRow(chars[4], chars[5]))
+
+	return result
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+/*
+Chooses the character a pixel is drawn with, for mapping logic other than the built-in character ramps, such as
+matching glyph coverage. Set it through CharOptions.Matcher, after which ConvertToAsciiCharsWithOptions() calls
+Match() for every non-transparent pixel. Match() may be called from multiple goroutines if conversions run
+concurrently.
+*/
+type CharMatcher interface {
+	Match(pixel AsciiPixel) rune
+}
+
+/*
+Maps pixels to characters of Ramp by their brightness, the same way ConvertToAsciiChars() does without a
+matcher. Ramp is ordered from darkest to lightest, and the 10 default ascii characters are used if it's empty.
+Useful as the fallback of custom CharMatcher implementations.
+*/
+type RampMatcher struct {
+	Ramp []rune
+}
+
+// Returns the character of m.Ramp for the brightness of pixel, using its 16-bit depth if PixelOptions.HighPrecision was set
+func (m RampMatcher) Match(pixel AsciiPixel) rune {
+	ramp := m.Ramp
+	if len(ramp) == 0 {
+		ramp = []rune(asciiTableSimple)
+	}
+
+	value, maxValue := depthValue(pixel)
+	return ramp[depthIndex(value, maxValue, len(ramp))]
+}
+
+// Returns the grayscale depth, from 0 to 255, that characters are chosen by
+func (pixel AsciiPixel) Depth() uint32 {
+	return pixel.charDepth
+}
+
+// Returns the RGB values of the pixel, from 0 to 255
+func (pixel AsciiPixel) RGB() [3]uint32 {
+	return pixel.rgbValue
+}
+
+// Returns the alpha value of the pixel, from 0 for fully transparent to 255 for opaque
+func (pixel AsciiPixel) Alpha() uint32 {
+	return pixel.alpha
+}
+
+// Returns whether the pixel is an edge and the angle of its gradient in radians. Only set when PixelOptions.EdgeMode is set
+func (pixel AsciiPixel) Edge() (bool, float64) {
+	return pixel.isEdge, pixel.edgeAngle
+}
@@ -0,0 +1,208 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import "math"
+
+// Metrics selectable through CharOptions.ColorDistance for matching colors against palettes
+const (
+	// CIEDE2000 distance in CIE Lab space, which is the closest to perceived difference
+	ColorDistanceCIEDE2000 = "ciede2000"
+
+	// Euclidean distance in CIE Lab space. Faster than CIEDE2000, but less accurate for saturated colors
+	ColorDistanceCIE76 = "cie76"
+
+	// Squared RGB distance weighted by the mean red value. Fastest, but picks visibly wrong colors more often
+	ColorDistanceRedmean = "redmean"
+)
+
+// CIE Lab values of xterm256Palette, converted once so palette entries aren't converted for every character
+var xterm256Lab = buildXterm256Lab()
+
+func buildXterm256Lab() [256][3]float64 {
+	var labColors [256][3]float64
+	for i, rgb := range xterm256Palette {
+		labColors[i] = rgbToLab(rgb)
+	}
+	return labColors
+}
+
+// Returns the index of the color closest to rgb out of xterm256Palette[start:end], measured with passed metric
+func nearestPaletteColor(rgb [3]int, start, end int, metric string) int {
+	return start + nearestColor(rgb, xterm256Palette[start:end], xterm256Lab[start:end], metric)
+}
+
+/*
+Returns the index of the color closest to rgb out of palette, whose CIE Lab values are labPalette, measured with
+passed metric. Metrics other than ColorDistanceCIE76 and ColorDistanceRedmean use ColorDistanceCIEDE2000.
+*/
+func nearestColor(rgb [3]int, palette [][3]int, labPalette [][3]float64, metric string) int {
+	best := 0
+	bestDistance := math.Inf(1)
+
+	var lab [3]float64
+	if metric != ColorDistanceRedmean {
+		lab = rgbToLab(rgb)
+	}
+
+	for i := range palette {
+		var distance float64
+
+		switch metric {
+		case ColorDistanceRedmean:
+			distance = float64(weightedColorDistance(rgb, palette[i]))
+		case ColorDistanceCIE76:
+			distance = cie76(lab, labPalette[i])
+		default:
+			distance = ciede2000(lab, labPalette[i])
+		}
+
+		if distance < bestDistance {
+			best = i
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
+
+// Converts an sRGB color to CIE Lab under the D65 white point
+func rgbToLab(rgb [3]int) [3]float64 {
+	var linear [3]float64
+	for c := 0; c < 3; c++ {
+		value := float64(rgb[c]) / 255
+		if value <= 0.04045 {
+			linear[c] = value / 12.92
+		} else {
+			linear[c] = math.Pow((value+0.055)/1.055, 2.4)
+		}
+	}
+
+	// Linear sRGB to CIE XYZ, normalized by the D65 white point
+	x := (0.4124564*linear[0] + 0.3575761*linear[1] + 0.1804375*linear[2]) / 0.95047
+	y := 0.2126729*linear[0] + 0.7151522*linear[1] + 0.0721750*linear[2]
+	z := (0.0193339*linear[0] + 0.1191920*linear[1] + 0.9503041*linear[2]) / 1.08883
+
+	fx, fy, fz := labF(x), labF(y), labF(z)
+
+	return [3]float64{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+func labF(t float64) float64 {
+	const delta = 6.0 / 29
+	if t > delta*delta*delta {
+		return math.Cbrt(t)
+	}
+	return t/(3*delta*delta) + 4.0/29
+}
+
+// Squared CIE76 distance, which is euclidean distance in Lab space. Squaring is skipped since only order matters
+func cie76(lab1, lab2 [3]float64) float64 {
+	dL := lab1[0] - lab2[0]
+	da := lab1[1] - lab2[1]
+	db := lab1[2] - lab2[2]
+	return dL*dL + da*da + db*db
+}
+
+/*
+CIEDE2000 color difference with unit weighting factors, as defined by Sharma, Wu and Dalal in "The CIEDE2000
+Color-Difference Formula: Implementation Notes, Supplementary Test Data, and Mathematical Observations".
+*/
+func ciede2000(lab1, lab2 [3]float64) float64 {
+	const pow25To7 = 6103515625.0 // 25^7
+
+	l1, a1, b1 := lab1[0], lab1[1], lab1[2]
+	l2, a2, b2 := lab2[0], lab2[1], lab2[2]
+
+	// Chroma is adjusted so neutral colors aren't treated as having a hue
+	cMean := (math.Hypot(a1, b1) + math.Hypot(a2, b2)) / 2
+	cMean7 := math.Pow(cMean, 7)
+	g := 0.5 * (1 - math.Sqrt(cMean7/(cMean7+pow25To7)))
+
+	a1p := a1 * (1 + g)
+	a2p := a2 * (1 + g)
+	c1p := math.Hypot(a1p, b1)
+	c2p := math.Hypot(a2p, b2)
+	h1p := hueAngle(b1, a1p)
+	h2p := hueAngle(b2, a2p)
+
+	dLp := l2 - l1
+	dCp := c2p - c1p
+
+	var dhp float64
+	if c1p*c2p != 0 {
+		dhp = h2p - h1p
+		if dhp > 180 {
+			dhp -= 360
+		} else if dhp < -180 {
+			dhp += 360
+		}
+	}
+	dHp := 2 * math.Sqrt(c1p*c2p) * math.Sin(degToRad(dhp/2))
+
+	lMean := (l1 + l2) / 2
+	cMeanP := (c1p + c2p) / 2
+
+	hMeanP := h1p + h2p
+	if c1p*c2p != 0 {
+		if math.Abs(h1p-h2p) > 180 {
+			if hMeanP < 360 {
+				hMeanP += 360
+			} else {
+				hMeanP -= 360
+			}
+		}
+		hMeanP /= 2
+	}
+
+	t := 1 - 0.17*math.Cos(degToRad(hMeanP-30)) +
+		0.24*math.Cos(degToRad(2*hMeanP)) +
+		0.32*math.Cos(degToRad(3*hMeanP+6)) -
+		0.20*math.Cos(degToRad(4*hMeanP-63))
+
+	dTheta := 30 * math.Exp(-math.Pow((hMeanP-275)/25, 2))
+	cMeanP7 := math.Pow(cMeanP, 7)
+	rc := 2 * math.Sqrt(cMeanP7/(cMeanP7+pow25To7))
+
+	lMean50 := (lMean - 50) * (lMean - 50)
+	sl := 1 + 0.015*lMean50/math.Sqrt(20+lMean50)
+	sc := 1 + 0.045*cMeanP
+	sh := 1 + 0.015*cMeanP*t
+	rt := -math.Sin(degToRad(2*dTheta)) * rc
+
+	lTerm := dLp / sl
+	cTerm := dCp / sc
+	hTerm := dHp / sh
+
+	return math.Sqrt(lTerm*lTerm + cTerm*cTerm + hTerm*hTerm + rt*cTerm*hTerm)
+}
+
+// Returns the hue angle of a and b in degrees, from 0 to 360
+func hueAngle(b, a float64) float64 {
+	if a == 0 && b == 0 {
+		return 0
+	}
+	h := math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+func degToRad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Returns an error for color effects that can't be applied
+func checkColorEffectOptions(opts PixelOptions) error {
+	switch opts.Rainbow {
+	case "", "column", "row", "brightness":
+		return nil
+	default:
+		return fmt.Errorf("unknown rainbow mode %q", opts.Rainbow)
+	}
+}
+
+/*
+Replaces the RGB values of each AsciiPixel in imgSet with a fully saturated hue that cycles across the image's
+columns or rows, or with its charDepth for "brightness", where hues run from red for the darkest pixels to
+magenta for the brightest ones so they don't wrap back around to red.
+*/
+func rainbowImgSet(imgSet [][]AsciiPixel, mode string) {
+
+	height := len(imgSet)
+
+	for y := range imgSet {
+		width := len(imgSet[y])
+
+		for x := range imgSet[y] {
+			pixel := &imgSet[y][x]
+
+			var hue float64
+			switch mode {
+			case "column":
+				hue = 360 * float64(x) / float64(width)
+			case "row":
+				hue = 360 * float64(y) / float64(height)
+			case "brightness":
+				hue = 300 * float64(pixel.charDepth) / MAX_VAL
+			}
+
+			pixel.rgbValue = hslToRgb(hue, 1, 0.5)
+		}
+	}
+}
+
+/*
+Replaces the RGB values of each AsciiPixel in imgSet with a color interpolated linearly between start and end by
+its charDepth, so darkest pixels get start and brightest ones get end regardless of their original colors.
+*/
+func gradientImgSet(imgSet [][]AsciiPixel, start, end color.Color) {
+
+	from := toRgb255(start)
+	to := toRgb255(end)
+
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			pixel := &imgSet[y][x]
+
+			t := float64(pixel.charDepth) / MAX_VAL
+			for c := 0; c < 3; c++ {
+				pixel.rgbValue[c] = uint32(math.Round(from[c] + (to[c]-from[c])*t))
+			}
+		}
+	}
+}
+
+// Returns red, green and blue values of c from 0 to 255
+func toRgb255(c color.Color) [3]float64 {
+	r, g, b, _ := c.RGBA()
+	return [3]float64{float64(r / 257), float64(g / 257), float64(b / 257)}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// Color modes selectable through CharOptions.ColorMode
+const (
+	// 24-bit RGB escape codes
+	ColorModeTrueColor = "truecolor"
+
+	// Nearest color from the xterm 256 color palette
+	ColorMode256 = "256"
+
+	// Nearest of the 8 standard ANSI colors
+	ColorMode8 = "8"
+
+	// Nearest of the 8 standard ANSI colors and their bright variants
+	ColorMode16 = "16"
+
+	// No escape codes at all, even if a coloring flag is set
+	ColorModePlain = "plain"
+
+	// Richest of the above that the terminal advertises, resolved with DetectColorMode()
+	ColorModeAuto = "auto"
+)
+
+// Optional settings for converting AsciiPixel instances into AsciiChar instances.
+// The zero value keeps the default behavior
+type CharOptions struct {
+	// Escape codes used for colored characters. Either ColorModeTrueColor, ColorMode256, ColorMode16,
+	// ColorMode8, ColorModePlain or ColorModeAuto. Defaults to ColorModeTrueColor when empty
+	ColorMode string
+
+	// Metric used to find the nearest palette color for ColorMode256, ColorMode16 and ColorMode8. Either
+	// ColorDistanceCIEDE2000, ColorDistanceCIE76 or ColorDistanceRedmean, from most accurate to fastest.
+	// Defaults to ColorDistanceCIEDE2000 when empty
+	ColorDistance string
+
+	// Invert the brightness-to-character mapping without inverting colors, so dark pixels get dense
+	// characters and light pixels get sparse ones. For braille and quadrant art, this inverts which dots
+	// or quadrants are filled. Combined with negative, the two inversions cancel out for character selection
+	Invert bool
+
+	// Reverse the order of the active character set, whether it's the default one or a custom map.
+	// Only used by ConvertToAsciiCharsWithOptions()
+	ReverseMap bool
+
+	// Pixels with an alpha value, from 0 to 255, below this are drawn as TransparentChar instead of being
+	// mapped like black pixels. Braille, quadrant and half block characters are only replaced when all of
+	// their pixels are transparent, while transparent pixels never fill dots or quadrants. Disabled when 0
+	AlphaThreshold int
+
+	// Uncolored character drawn for transparent pixels. Defaults to a space when empty
+	TransparentChar string
+
+	// Color each character's background with a darkened pixel color and the character itself with a lightened one,
+	// instead of only coloring one of them. Only applies to the colors of pixels, not the font color, and is unused
+	// for half block and background block characters, which already set both
+	DualColor bool
+
+	// Dots per braille character in ConvertToBrailleCharsWithOptions(), which must match PixelOptions.BrailleDots
+	// that imgSet was converted with. Either 8 for 2x4 dots or 6 for 2x3 dots. Defaults to 8 for any other value
+	BrailleDots int
+
+	// Chooses the character of each pixel in ConvertToAsciiCharsWithOptions() instead of the built-in character
+	// ramp, which makes complex, customMap, Invert and ReverseMap unused, while negative still inverts colors.
+	// Braille, block and edge characters are found from dots, quadrants and edge angles instead, so they don't
+	// use it. Unused when nil
+	Matcher CharMatcher
+}
+
+/*
+Returns the richest color mode the terminal advertises through its environment variables, in this order:
+
+  - ColorModeTrueColor if COLORTERM is "truecolor" or "24bit"
+  - ColorMode256 if TERM contains "256color"
+  - ColorModePlain if TERM is empty or "dumb"
+  - ColorMode16 for any other TERM
+*/
+func DetectColorMode() string {
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return ColorModeTrueColor
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	if strings.Contains(term, "256color") {
+		return ColorMode256
+	}
+	if term == "" || term == "dumb" {
+		return ColorModePlain
+	}
+
+	return ColorMode16
+}
+
+// Replaces ColorModeAuto with the detected color mode, leaving explicitly set modes untouched
+func resolveColorMode(opts CharOptions) CharOptions {
+	if opts.ColorMode == ColorModeAuto {
+		opts.ColorMode = DetectColorMode()
+	}
+	return opts
+}
+
+// RGB values of the xterm 256 color palette. The first 16 colors are the standard and bright ANSI colors
+var xterm256Palette = buildXterm256Palette()
+
+func buildXterm256Palette() [256][3]int {
+	var colors [256][3]int
+
+	ansiColors := [16][3]int{
+		{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0},
+		{0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+		{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+		{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+	copy(colors[:16], ansiColors[:])
+
+	// 6x6x6 color cube
+	levels := [6]int{0, 95, 135, 175, 215, 255}
+	for i := 0; i < 216; i++ {
+		colors[16+i] = [3]int{levels[i/36], levels[(i/6)%6], levels[i%6]}
+	}
+
+	// Grayscale ramp
+	for i := 0; i < 24; i++ {
+		gray := 8 + i*10
+		colors[232+i] = [3]int{gray, gray, gray}
+	}
+
+	return colors
+}
+
+/*
+Returns the index of the xterm 256 color closest to rgb according to metric, out of the color cube and grayscale
+ramp. The first 16 colors are skipped since terminals are free to redefine them.
+*/
+func nearestXterm256(rgb [3]int, metric string) uint8 {
+	return uint8(nearestPaletteColor(rgb, 16, 256, metric))
+}
+
+// Returns the index of the ANSI color closest to rgb according to metric, out of the first colorCount colors of the xterm palette
+func nearestAnsi(rgb [3]int, colorCount int, metric string) int {
+	return nearestPaletteColor(rgb, 0, colorCount, metric)
+}
+
+// Returns the SGR code of the passed ANSI color index, i.e. 30-37 and 90-97 for foreground, or 40-47 and 100-107 for background
+func ansiCode(index int, isBg bool) color.Color {
+	code := 30 + index
+	if index >= 8 {
+		code = 90 + index - 8
+	}
+	if isBg {
+		code += 10
+	}
+	return color.Color(code)
+}
+
+// Squared "redmean" color distance, scaled by 256 to stay in integers. Used for ColorDistanceRedmean
+func weightedColorDistance(c1, c2 [3]int) int {
+	rMean := (c1[0] + c2[0]) / 2
+	r := c1[0] - c2[0]
+	g := c1[1] - c2[1]
+	b := c1[2] - c2[2]
+
+	return (512+rMean)*r*r + 1024*g*g + (767-rMean)*b*b
+}
+
+// Returns text wrapped in escape codes of opts.ColorMode for passed RGB value, set as either foreground or background color
+func colorText(text string, rgb [3]int, isBg bool, opts CharOptions) string {
+	switch opts.ColorMode {
+	case ColorModePlain:
+		return text
+
+	case ColorMode256:
+		return color.C256(nearestXterm256(rgb, opts.ColorDistance), isBg).Sprint(text)
+
+	case ColorMode16, ColorMode8:
+		return ansiCode(nearestAnsi(rgb, ansiColorCount(opts.ColorMode), opts.ColorDistance), isBg).Sprint(text)
+
+	default:
+		tag := "fg"
+		if isBg {
+			tag = "bg"
+		}
+		return color.Sprintf("<"+tag+"="+rgbString(rgb)+">%v</>", text)
+	}
+}
+
+// Fraction of a pixel's color kept for its background, and how far its foreground is moved towards white, with opts.DualColor
+const (
+	dualColorBgScale   = 0.4
+	dualColorFgLighten = 0.35
+)
+
+// Same as colorText(), except that both colors are set from rgb if opts.DualColor is set, ignoring isBg
+func pixelColorText(text string, rgb [3]int, isBg bool, opts CharOptions) string {
+	if !opts.DualColor {
+		return colorText(text, rgb, isBg, opts)
+	}
+
+	var fg, bg [3]int
+	for c := 0; c < 3; c++ {
+		bg[c] = int(math.Round(float64(rgb[c]) * dualColorBgScale))
+		fg[c] = int(math.Round(float64(rgb[c]) + float64(255-rgb[c])*dualColorFgLighten))
+	}
+	return colorTextDual(text, fg, bg, opts)
+}
+
+// Returns text wrapped in escape codes of opts.ColorMode for both foreground and background colors
+func colorTextDual(text string, fg, bg [3]int, opts CharOptions) string {
+	switch opts.ColorMode {
+	case ColorModePlain:
+		return text
+
+	case ColorMode256:
+		return color.S256(nearestXterm256(fg, opts.ColorDistance), nearestXterm256(bg, opts.ColorDistance)).Sprint(text)
+
+	case ColorMode16, ColorMode8:
+		colorCount := ansiColorCount(opts.ColorMode)
+		return color.New(
+			ansiCode(nearestAnsi(fg, colorCount, opts.ColorDistance), false),
+			ansiCode(nearestAnsi(bg, colorCount, opts.ColorDistance), true),
+		).Sprint(text)
+
+	default:
+		return color.Sprintf("<fg="+rgbString(fg)+";bg="+rgbString(bg)+">%v</>", text)
+	}
+}
+
+func ansiColorCount(colorMode string) int {
+	if colorMode == ColorMode8 {
+		return 8
+	}
+	return 16
+}
+
+func rgbString(rgb [3]int) string {
+	return strconv.Itoa(rgb[0]) + "," + strconv.Itoa(rgb[1]) + "," + strconv.Itoa(rgb[2])
+}
+
+func toIntRgb(rgb [3]uint32) [3]int {
+	return [3]int{int(rgb[0]), int(rgb[1]), int(rgb[2])}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"bytes"
+	"image"
+	"sync"
+)
+
+/*
+Converts images to ascii art strings with the same Options, reusing the AsciiPixel slices of previous conversions
+of the same size along with the buffer the string is built in. This avoids allocating them again for every image
+when many images of the same size are converted, such as frames of a gif.
+
+A Converter is safe for concurrent use, since each call to Convert() takes its own buffers from a sync.Pool and its
+Options aren't changed after NewConverter(). Returned strings are copies and stay valid after later calls.
+*/
+type Converter struct {
+	opts Options
+
+	// Hold *[][]AsciiPixel and *bytes.Buffer values
+	pixelPool  sync.Pool
+	bufferPool sync.Pool
+}
+
+// Returns a Converter for passed options. opts shouldn't be changed afterwards, since its slices are shared
+func NewConverter(opts Options) *Converter {
+	return &Converter{opts: opts}
+}
+
+/*
+Converts img to ascii art the same way as ConvertWithOptions(), returning it as a string of lines separated by
+newlines. Characters are colored if Options.Colored is set or half block characters are used, drawn in
+Options.FontColor if it's set, and left uncolored otherwise.
+*/
+func (c *Converter) Convert(img image.Image) (string, error) {
+
+	var buf [][]AsciiPixel
+	if pooled, ok := c.pixelPool.Get().(*[][]AsciiPixel); ok {
+		buf = *pooled
+	}
+
+	asciiSet, imgSet, err := convertWithOptions(img, c.opts, buf)
+	if err != nil {
+		return "", err
+	}
+	c.pixelPool.Put(&imgSet)
+
+	sb, ok := c.bufferPool.Get().(*bytes.Buffer)
+	if !ok {
+		sb = new(bytes.Buffer)
+	}
+	sb.Reset()
+	defer c.bufferPool.Put(sb)
+
+	colored := c.opts.Colored || c.opts.Pixel.HalfBlock || c.opts.BgBlock
+
+	for i, line := range asciiSet {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+
+		for _, char := range line {
+			if colored {
+				sb.WriteString(char.OriginalColor)
+			} else if c.opts.FontColor != nil && *c.opts.FontColor != [3]int{255, 255, 255} {
+				sb.WriteString(char.SetColor)
+			} else {
+				sb.WriteString(char.Simple)
+			}
+		}
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Size glyphs are rendered at to measure their coverage, large enough for thin strokes to count
+const coverageFontSize = 32
+
+// Coverage tables already measured, keyed by font and character set, since rendering every glyph is slow
+var (
+	coverageCacheMutex sync.Mutex
+	coverageCache      = map[coverageKey]coverageTable{}
+)
+
+type coverageKey struct {
+	font  *truetype.Font
+	chars string
+}
+
+// Characters sorted by the fraction of their cell covered by ink, from least to most
+type coverageTable struct {
+	chars    []rune
+	coverage []float64
+}
+
+/*
+Maps pixels to the characters whose glyphs cover the closest fraction of their cells with ink, as they're rendered
+in a font, so brightness follows what's actually drawn instead of a hand-ordered ramp. Create it with
+NewCoverageMatcher().
+*/
+type CoverageMatcher struct {
+	table coverageTable
+
+	// Choose characters from the opposite end of the coverage range, e.g. for dark text on a light background
+	Invert bool
+}
+
+/*
+Returns a CoverageMatcher for the characters ConvertToAsciiChars() would pick from with passed complex and customMap,
+as they're rendered in f. Each glyph is rendered once and the measured coverage is cached per font and character set.
+Characters that f has no glyph for are left out, and an error is returned if none of them are left.
+*/
+func NewCoverageMatcher(f *truetype.Font, complex bool, customMap string) (*CoverageMatcher, error) {
+
+	if f == nil {
+		return nil, fmt.Errorf("no font passed for coverage matching")
+	}
+
+	chars := customMap
+	if chars == "" {
+		chars = asciiTableSimple
+		if complex {
+			chars = asciiTableDetailed
+		}
+	}
+
+	key := coverageKey{f, chars}
+
+	coverageCacheMutex.Lock()
+	defer coverageCacheMutex.Unlock()
+
+	table, ok := coverageCache[key]
+	if !ok {
+		table = measureCoverage(f, chars)
+		if len(table.chars) == 0 {
+			return nil, fmt.Errorf("font has no glyphs for characters %q", chars)
+		}
+		coverageCache[key] = table
+	}
+
+	return &CoverageMatcher{table: table}, nil
+}
+
+// Renders each character of chars in f and returns them sorted by the fraction of their cell covered by ink
+func measureCoverage(f *truetype.Font, chars string) coverageTable {
+
+	face := truetype.NewFace(f, &truetype.Options{Size: coverageFontSize, Hinting: font.HintingNone})
+	defer face.Close()
+
+	metrics := face.Metrics()
+	cellHeight := (metrics.Ascent + metrics.Descent).Ceil()
+
+	var table coverageTable
+	seen := map[rune]bool{}
+
+	for _, char := range chars {
+		if seen[char] {
+			continue
+		}
+		seen[char] = true
+
+		advance, ok := face.GlyphAdvance(char)
+		if !ok || advance <= 0 {
+			continue
+		}
+		cellWidth := advance.Ceil()
+
+		cell := image.NewAlpha(image.Rect(0, 0, cellWidth, cellHeight))
+		drawer := font.Drawer{
+			Dst:  cell,
+			Src:  image.Opaque,
+			Face: face,
+			Dot:  fixed.Point26_6{Y: metrics.Ascent},
+		}
+		drawer.DrawString(string(char))
+
+		var ink int
+		for _, alpha := range cell.Pix {
+			ink += int(alpha)
+		}
+
+		table.chars = append(table.chars, char)
+		table.coverage = append(table.coverage, float64(ink)/float64(len(cell.Pix)*0xff))
+	}
+
+	sort.Stable(table)
+	return table
+}
+
+func (t coverageTable) Len() int           { return len(t.chars) }
+func (t coverageTable) Less(i, j int) bool { return t.coverage[i] < t.coverage[j] }
+func (t coverageTable) Swap(i, j int) {
+	t.chars[i], t.chars[j] = t.chars[j], t.chars[i]
+	t.coverage[i], t.coverage[j] = t.coverage[j], t.coverage[i]
+}
+
+/*
+Returns the character whose coverage is closest to the brightness of pixel. Brightness is scaled to the range
+between the least and most covered characters, so dark pixels get the sparsest character and light pixels the
+densest one, the same way the default ramps are ordered.
+*/
+func (m *CoverageMatcher) Match(pixel AsciiPixel) rune {
+
+	chars, coverage := m.table.chars, m.table.coverage
+
+	value, maxValue := depthValue(pixel)
+	fraction := value / maxValue
+	if m.Invert {
+		fraction = 1 - fraction
+	}
+
+	lowest, highest := coverage[0], coverage[len(coverage)-1]
+	target := lowest + fraction*(highest-lowest)
+
+	i := sort.SearchFloat64s(coverage, target)
+	if i == len(coverage) {
+		return chars[i-1]
+	}
+	if i > 0 && target-coverage[i-1] < coverage[i]-target {
+		return chars[i-1]
+	}
+	return chars[i]
+}
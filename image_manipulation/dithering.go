@@ -0,0 +1,203 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import "fmt"
+
+// Each entry diffuses a fraction of the quantization error to the pixel at [dy, dx] relative to the current one
+type diffusionWeight struct {
+	dy, dx int
+	weight float64
+}
+
+// Error diffusion kernels selectable through PixelOptions.DitherMode
+var ditherKernels = map[string][]diffusionWeight{
+	"floyd-steinberg": {
+		{0, 1, 7.0 / 16},
+		{1, -1, 3.0 / 16},
+		{1, 0, 5.0 / 16},
+		{1, 1, 1.0 / 16},
+	},
+
+	// Only 6/8 of the error is propagated, which keeps high-contrast areas cleaner
+	"atkinson": {
+		{0, 1, 1.0 / 8},
+		{0, 2, 1.0 / 8},
+		{1, -1, 1.0 / 8},
+		{1, 0, 1.0 / 8},
+		{1, 1, 1.0 / 8},
+		{2, 0, 1.0 / 8},
+	},
+}
+
+// Bayer threshold matrices for ordered dithering, keyed by their size
+var bayerMatrices = map[int][][]int{
+	2: bayerMatrix(2),
+	4: bayerMatrix(4),
+	8: bayerMatrix(8),
+}
+
+// Builds a Bayer matrix of size n (a power of 2) recursively from the 2x2 matrix
+func bayerMatrix(n int) [][]int {
+	if n == 1 {
+		return [][]int{{0}}
+	}
+
+	half := bayerMatrix(n / 2)
+	matrix := make([][]int, n)
+	for y := range matrix {
+		matrix[y] = make([]int, n)
+	}
+
+	for y := 0; y < n/2; y++ {
+		for x := 0; x < n/2; x++ {
+			value := 4 * half[y][x]
+			matrix[y][x] = value
+			matrix[y][x+n/2] = value + 2
+			matrix[y+n/2][x] = value + 3
+			matrix[y+n/2][x+n/2] = value + 1
+		}
+	}
+
+	return matrix
+}
+
+// Returns an error for dithering options that can't be applied
+func checkDitherOptions(opts PixelOptions) error {
+	if opts.Dithering < 0 || opts.Dithering > 1 {
+		return fmt.Errorf("dithering strength must be between 0.0 and 1.0")
+	}
+
+	if opts.DitherMode == "bayer" {
+		if _, ok := bayerMatrices[opts.BayerSize]; !ok && opts.BayerSize != 0 {
+			return fmt.Errorf("bayer matrix size must be 2, 4 or 8")
+		}
+		return nil
+	}
+
+	if _, ok := ditherKernels[opts.DitherMode]; !ok && opts.DitherMode != "" {
+		return fmt.Errorf("unknown dither mode %q", opts.DitherMode)
+	}
+
+	return nil
+}
+
+// Dithers passed imgSet according to opts.DitherMode. Options should be checked with checkDitherOptions() beforehand
+func ditherImgSet(imgSet [][]AsciiPixel, opts PixelOptions) {
+
+	if opts.DitherLevels < 2 || len(imgSet) == 0 {
+		return
+	}
+
+	switch opts.DitherMode {
+	case "bayer":
+		size := opts.BayerSize
+		if size == 0 {
+			size = 4
+		}
+		orderedDither(imgSet, bayerMatrices[size], opts.DitherLevels, opts.Dithering)
+
+	case "":
+		diffuseError(imgSet, ditherKernels["floyd-steinberg"], opts.DitherLevels, opts.Dithering)
+
+	default:
+		diffuseError(imgSet, ditherKernels[opts.DitherMode], opts.DitherLevels, opts.Dithering)
+	}
+}
+
+/*
+Runs error diffusion dithering with the passed kernel on the charDepth of each AsciiPixel in imgSet. Values are
+quantized to the center of the bucket they'd fall in when mapped against the given number of levels, so character
+selection later down the line stays the same for undithered values. Strength scales how much of the quantization
+error is diffused.
+*/
+func diffuseError(imgSet [][]AsciiPixel, kernel []diffusionWeight, levels int, strength float64) {
+
+	height := len(imgSet)
+	width := len(imgSet[0])
+
+	// Errors need to accumulate beyond 0-255, so a float buffer is used instead of charDepth directly
+	buffer := make([][]float64, height)
+	for y := range imgSet {
+		buffer[y] = make([]float64, width)
+		for x := range imgSet[y] {
+			buffer[y][x] = float64(imgSet[y][x].charDepth)
+		}
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+
+			oldValue := clampFloat(buffer[y][x], 0, MAX_VAL)
+			newValue := quantizeDepth(oldValue, levels)
+			quantError := (oldValue - newValue) * strength
+
+			for _, w := range kernel {
+				ny, nx := y+w.dy, x+w.dx
+				if ny < height && nx >= 0 && nx < width {
+					buffer[ny][nx] += quantError * w.weight
+				}
+			}
+
+			imgSet[y][x].charDepth = uint32(newValue)
+			imgSet[y][x].hasDepth16 = false
+		}
+	}
+}
+
+/*
+Offsets the charDepth of each AsciiPixel in imgSet by the Bayer matrix entry at its position before quantizing it.
+Since every pixel is handled independently of its neighbors, the result is deterministic and tiles across images.
+Strength scales the offset, which is at most half a bucket in either direction.
+*/
+func orderedDither(imgSet [][]AsciiPixel, matrix [][]int, levels int, strength float64) {
+
+	n := len(matrix)
+	bucketSize := MAX_VAL / float64(levels)
+
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			offset := (float64(matrix[y%n][x%n])+0.5)/float64(n*n) - 0.5
+
+			value := float64(imgSet[y][x].charDepth) + offset*bucketSize*strength
+			imgSet[y][x].charDepth = uint32(quantizeDepth(clampFloat(value, 0, MAX_VAL), levels))
+			imgSet[y][x].hasDepth16 = false
+		}
+	}
+}
+
+// Returns the center value of the bucket that value lands in when 0-255 is split into the given number of levels
+func quantizeDepth(value float64, levels int) float64 {
+	bucketSize := MAX_VAL / float64(levels)
+
+	bucket := int(value / bucketSize)
+	if bucket >= levels {
+		bucket = levels - 1
+	}
+
+	return (float64(bucket) + 0.5) * bucketSize
+}
+
+func clampFloat(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,344 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"fmt"
+	"math"
+)
+
+// Used when PixelOptions.EdgeThreshold or PixelOptions.EdgeBlur is set to 0
+const (
+	defaultEdgeThreshold = 64
+	defaultEdgeBlur      = 1.4
+)
+
+// Checks edge detection options, returning an error for ones that can't be applied
+func checkEdgeOptions(opts PixelOptions) error {
+	if opts.EdgeMode != "" && opts.EdgeMode != "sobel" && opts.EdgeMode != "canny" {
+		return fmt.Errorf("unknown edge mode %q", opts.EdgeMode)
+	}
+	if opts.EdgeThreshold < 0 || opts.EdgeThreshold > 255 {
+		return fmt.Errorf("edge threshold must be between 0 and 255")
+	}
+	if opts.EdgeLowThreshold < 0 || opts.EdgeLowThreshold > 255 {
+		return fmt.Errorf("low edge threshold must be between 0 and 255")
+	}
+	if opts.EdgeLowThreshold > opts.EdgeThreshold && opts.EdgeThreshold != 0 {
+		return fmt.Errorf("low edge threshold can't be higher than edge threshold")
+	}
+	if opts.EdgeBlur < 0 {
+		return fmt.Errorf("edge blur must be greater than 0")
+	}
+	return nil
+}
+
+/*
+Runs a Sobel operator over the charDepth of each AsciiPixel in imgSet, storing the gradient direction of each pixel
+and marking it as an edge if the gradient magnitude reaches the threshold. Magnitudes are scaled down to roughly the
+0-255 range of grayscale values, and pixels outside the image are treated as copies of the nearest edge pixel.
+*/
+func sobelEdges(imgSet [][]AsciiPixel, threshold int) {
+
+	if threshold == 0 {
+		threshold = defaultEdgeThreshold
+	}
+
+	magnitudes, angles := sobelGradients(depthBuffer(imgSet))
+
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			imgSet[y][x].edgeAngle = angles[y][x]
+			imgSet[y][x].isEdge = magnitudes[y][x] >= float64(threshold)
+		}
+	}
+}
+
+/*
+Runs a Canny edge detector over the charDepth of each AsciiPixel in imgSet. Values are smoothed with a Gaussian
+blur of the passed sigma before Sobel gradients are calculated, after which only pixels whose magnitude is the
+largest along their gradient direction are kept, so edges end up a single pixel wide. Of those, pixels reaching
+highThreshold are marked as edges, along with pixels reaching lowThreshold that are connected to them.
+*/
+func cannyEdges(imgSet [][]AsciiPixel, lowThreshold, highThreshold int, sigma float64) {
+
+	if highThreshold == 0 {
+		highThreshold = defaultEdgeThreshold
+	}
+	if lowThreshold == 0 {
+		lowThreshold = highThreshold / 2
+	}
+	if sigma == 0 {
+		sigma = defaultEdgeBlur
+	}
+
+	magnitudes, angles := sobelGradients(gaussianBlur(depthBuffer(imgSet), sigma))
+	thinned := suppressNonMaximum(magnitudes, angles)
+
+	height := len(imgSet)
+	width := len(imgSet[0])
+
+	// Start from strong edges and follow weak edges connected to them in any of the 8 directions
+	var stack [][2]int
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			imgSet[y][x].edgeAngle = angles[y][x]
+			imgSet[y][x].isEdge = false
+
+			if thinned[y][x] >= float64(highThreshold) {
+				imgSet[y][x].isEdge = true
+				stack = append(stack, [2]int{y, x})
+			}
+		}
+	}
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				ny, nx := current[0]+dy, current[1]+dx
+				if ny < 0 || ny >= height || nx < 0 || nx >= width || imgSet[ny][nx].isEdge {
+					continue
+				}
+
+				if thinned[ny][nx] >= float64(lowThreshold) {
+					imgSet[ny][nx].isEdge = true
+					stack = append(stack, [2]int{ny, nx})
+				}
+			}
+		}
+	}
+}
+
+// Returns the charDepth of each AsciiPixel in imgSet as floats
+func depthBuffer(imgSet [][]AsciiPixel) [][]float64 {
+	buffer := make([][]float64, len(imgSet))
+	for y := range imgSet {
+		buffer[y] = make([]float64, len(imgSet[y]))
+		for x := range imgSet[y] {
+			buffer[y][x] = float64(imgSet[y][x].charDepth)
+		}
+	}
+	return buffer
+}
+
+// Returns a copy of buffer blurred with a separable Gaussian kernel reaching 3 sigma in each direction
+func gaussianBlur(buffer [][]float64, sigma float64) [][]float64 {
+
+	height := len(buffer)
+	width := len(buffer[0])
+
+	radius := int(math.Ceil(3 * sigma))
+	kernel := make([]float64, 2*radius+1)
+
+	var sum float64
+	for i := range kernel {
+		offset := float64(i - radius)
+		kernel[i] = math.Exp(-offset * offset / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+
+	horizontal := make([][]float64, height)
+	for y := 0; y < height; y++ {
+		horizontal[y] = make([]float64, width)
+		for x := 0; x < width; x++ {
+			for i, weight := range kernel {
+				horizontal[y][x] += weight * buffer[y][clampInt(x+i-radius, 0, width-1)]
+			}
+		}
+	}
+
+	blurred := make([][]float64, height)
+	for y := 0; y < height; y++ {
+		blurred[y] = make([]float64, width)
+		for x := 0; x < width; x++ {
+			for i, weight := range kernel {
+				blurred[y][x] += weight * horizontal[clampInt(y+i-radius, 0, height-1)][x]
+			}
+		}
+	}
+
+	return blurred
+}
+
+// Returns magnitudes with every pixel that isn't larger than both of its neighbors along its gradient direction set to 0
+func suppressNonMaximum(magnitudes, angles [][]float64) [][]float64 {
+
+	height := len(magnitudes)
+	width := len(magnitudes[0])
+
+	magnitudeAt := func(y, x int) float64 {
+		if y < 0 || y >= height || x < 0 || x >= width {
+			return 0
+		}
+		return magnitudes[y][x]
+	}
+
+	thinned := make([][]float64, height)
+
+	for y := 0; y < height; y++ {
+		thinned[y] = make([]float64, width)
+
+		for x := 0; x < width; x++ {
+			degrees := math.Mod(angles[y][x]*180/math.Pi+180, 180)
+
+			// Offset of the neighbor along the gradient, rounded to one of 4 directions
+			var dy, dx int
+			switch {
+			case degrees < 22.5 || degrees >= 157.5:
+				dy, dx = 0, 1
+			case degrees < 67.5:
+				dy, dx = 1, 1
+			case degrees < 112.5:
+				dy, dx = 1, 0
+			default:
+				dy, dx = 1, -1
+			}
+
+			magnitude := magnitudes[y][x]
+			if magnitude >= magnitudeAt(y+dy, x+dx) && magnitude >= magnitudeAt(y-dy, x-dx) {
+				thinned[y][x] = magnitude
+			}
+		}
+	}
+
+	return thinned
+}
+
+// Returns the gradient magnitude and direction in radians of each value of buffer
+func sobelGradients(buffer [][]float64) ([][]float64, [][]float64) {
+
+	height := len(buffer)
+	width := len(buffer[0])
+
+	depthAt := func(y, x int) float64 {
+		y = clampInt(y, 0, height-1)
+		x = clampInt(x, 0, width-1)
+		return buffer[y][x]
+	}
+
+	magnitudes := make([][]float64, height)
+	angles := make([][]float64, height)
+
+	for y := 0; y < height; y++ {
+		magnitudes[y] = make([]float64, width)
+		angles[y] = make([]float64, width)
+
+		for x := 0; x < width; x++ {
+			gx := (depthAt(y-1, x+1) + 2*depthAt(y, x+1) + depthAt(y+1, x+1)) -
+				(depthAt(y-1, x-1) + 2*depthAt(y, x-1) + depthAt(y+1, x-1))
+
+			gy := (depthAt(y+1, x-1) + 2*depthAt(y+1, x) + depthAt(y+1, x+1)) -
+				(depthAt(y-1, x-1) + 2*depthAt(y-1, x) + depthAt(y-1, x+1))
+
+			// Each kernel sums to at most 4 times the largest grayscale value
+			magnitudes[y][x] = math.Hypot(gx, gy) / 4
+			angles[y][x] = math.Atan2(gy, gx)
+		}
+	}
+
+	return magnitudes, angles
+}
+
+// Mirrors gradient directions of a flipped imgSet, since edges are detected before flipping
+func mirrorEdgeAngles(imgSet [][]AsciiPixel, flipX, flipY bool) {
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			if flipX {
+				imgSet[y][x].edgeAngle = math.Pi - imgSet[y][x].edgeAngle
+			}
+			if flipY {
+				imgSet[y][x].edgeAngle = -imgSet[y][x].edgeAngle
+			}
+		}
+	}
+}
+
+/*
+Returns the line character running along an edge with the passed gradient direction. Since the gradient points
+across the edge, a horizontal gradient gives a vertical line and so on. Positive y points downwards in images.
+*/
+func edgeLineChar(angle float64) string {
+
+	// Direction of a line is the same when rotated by 180 degrees
+	degrees := math.Mod(angle*180/math.Pi+180, 180)
+
+	switch {
+	case degrees < 22.5 || degrees >= 157.5:
+		return "|"
+	case degrees < 67.5:
+		return "/"
+	case degrees < 112.5:
+		return "-"
+	default:
+		return "\\"
+	}
+}
+
+/*
+Converts the 2D image_conversions.AsciiPixel slice of image data (each instance representing each compressed pixel of original image)
+to a 2D image_conversions.AsciiChar slice
+
+This function should only be used on an imgSet returned by ConvertToAsciiPixelsWithOptions() with PixelOptions.EdgeMode
+set. Each pixel marked as an edge is drawn as a line character following the edge's direction, while others are left
+as spaces. The negative parameter only inverts colors here, since inverting the character selection would draw lines
+everywhere but edges.
+*/
+func ConvertToEdgeChars(imgSet [][]AsciiPixel, negative, colored, colorBg bool, fontColor [3]int, opts CharOptions) [][]AsciiChar {
+
+	opts = resolveColorMode(opts)
+
+	result := make([][]AsciiChar, 0, len(imgSet))
+
+	for i := range imgSet {
+
+		tempSlice := make([]AsciiChar, 0, len(imgSet[i]))
+
+		for j := range imgSet[i] {
+			if isTransparent(imgSet[i][j], opts) {
+				tempSlice = append(tempSlice, transparentChar(opts))
+				continue
+			}
+
+			simple := " "
+			if imgSet[i][j].isEdge {
+				simple = edgeLineChar(imgSet[i][j].edgeAngle)
+			}
+
+			tempSlice = append(tempSlice, coloredChar(simple, imgSet[i][j], negative, colored, colorBg, fontColor, opts))
+		}
+
+		result = append(result, tempSlice)
+	}
+
+	return result
+}
+
+func clampInt(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
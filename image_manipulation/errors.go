@@ -0,0 +1,40 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import "errors"
+
+// Terminal size used when it can't be detected and isn't set through PixelOptions
+const (
+	FallbackTerminalWidth  = 80
+	FallbackTerminalHeight = 24
+)
+
+// Errors returned for dimensions that can't be used, wrapped with the offending values. Use errors.Is() to check for them
+var (
+	// The set width, or the width calculated from the set height, doesn't fit in the terminal
+	ErrWidthExceedsTerminal = errors.New("width exceeds terminal width")
+
+	// Width and height were both set without dimensions
+	ErrWidthAndHeightSet = errors.New("both width and height can't be set. Use dimensions instead")
+
+	// Font ratio is negative
+	ErrInvalidFontRatio = errors.New("font ratio must be greater than 0")
+
+	// Dimensions don't have exactly 2 values, or either of them is lower than 1
+	ErrInvalidDimensions = errors.New("invalid dimensions")
+)
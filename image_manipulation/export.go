@@ -0,0 +1,84 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+// Exported values of a single character of ascii art along with the pixel it was mapped from, for serializing
+// conversion results to formats such as JSON or placing them in widgets of other programs
+type Cell struct {
+	// Character the pixel was mapped to, without any escape codes
+	Char string `json:"char"`
+
+	// RGB values from 0 to 255 that the character is colored with. These are grayscale values unless the
+	// character was converted with colored set to true
+	Color [3]uint32 `json:"color"`
+
+	// RGB values of the background of half block characters, where it represents the lower pixel. Nil otherwise
+	Background *[3]uint32 `json:"background,omitempty"`
+
+	// Value from 0 to 255 that the character was chosen by, after adjustments and dithering
+	Depth uint32 `json:"depth"`
+
+	// Grayscale and RGB values of the pixel from 0 to 255
+	Gray uint32    `json:"gray"`
+	RGB  [3]uint32 `json:"rgb"`
+
+	// From 0 for fully transparent to 255 for opaque
+	Alpha uint32 `json:"alpha"`
+}
+
+/*
+Returns a Cell for each character of asciiSet, where asciiSet was converted from imgSet. For braille and block
+characters, which represent multiple pixels, the values of the top left pixel of each character are used, since
+that's the one their color is taken from. Background is set when each character covers two vertical pixels, as
+half block characters do.
+*/
+func GridCells(imgSet [][]AsciiPixel, asciiSet [][]AsciiChar) [][]Cell {
+
+	if len(imgSet) == 0 || len(asciiSet) == 0 || len(asciiSet[0]) == 0 {
+		return nil
+	}
+
+	rowStep := len(imgSet) / len(asciiSet)
+	colStep := len(imgSet[0]) / len(asciiSet[0])
+	isHalfBlock := rowStep == 2 && colStep == 1
+
+	cells := make([][]Cell, len(asciiSet))
+
+	for i, line := range asciiSet {
+		cells[i] = make([]Cell, len(line))
+
+		for j, char := range line {
+			pixel := imgSet[i*rowStep][j*colStep]
+
+			cells[i][j] = Cell{
+				Char:  char.Simple,
+				Color: char.RgbValue,
+				Depth: pixel.charDepth,
+				Gray:  pixel.grayscaleValue[0],
+				RGB:   pixel.rgbValue,
+				Alpha: pixel.alpha,
+			}
+
+			if isHalfBlock {
+				background := char.BgRgbValue
+				cells[i][j].Background = &background
+			}
+		}
+	}
+
+	return cells
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/disintegration/imaging"
+)
+
+// Returns an error for fit modes that can't be applied
+func checkFitOptions(opts PixelOptions) error {
+	switch opts.Fit {
+	case "", "stretch", "contain", "cover":
+		return nil
+	default:
+		return fmt.Errorf("unknown fit mode %q", opts.Fit)
+	}
+}
+
+// Returns opts.FontRatio, or 2 if it isn't set. Returns 1 if opts.NoAspectCorrection is set
+func fontRatioOf(opts PixelOptions) float64 {
+	if opts.NoAspectCorrection {
+		return 1
+	}
+	if opts.FontRatio == 0 {
+		return 2
+	}
+	return opts.FontRatio
+}
+
+/*
+Returns the size in characters that an image of passed bounds takes up in a box of boxWidth x boxHeight characters
+according to opts.Fit. With "contain", the largest size that keeps the image's aspect ratio within the box is
+returned, so one of its dimensions may be smaller than the box. Otherwise the box itself is returned, since
+"cover" crops the image to the box's aspect ratio with coverCrop() beforehand and "stretch" distorts it.
+*/
+func fitDimensions(bounds image.Rectangle, boxWidth, boxHeight int, opts PixelOptions) (int, int) {
+
+	if opts.Fit != "contain" {
+		return boxWidth, boxHeight
+	}
+
+	fontRatio := fontRatioOf(opts)
+
+	fitHeight := int(float64(keepAspectHeight(bounds, boxWidth)) / fontRatio)
+	if fitHeight <= boxHeight {
+		return boxWidth, maxInt(fitHeight, 1)
+	}
+
+	fitWidth := int(fontRatio * float64(keepAspectWidth(bounds, boxHeight)))
+	return maxInt(minInt(fitWidth, boxWidth), 1), boxHeight
+}
+
+/*
+For opts.Fit set to "cover", returns the centered region of img with the same aspect ratio as the box set in
+dimensions, taking the font ratio into account, so the resized image fills the box without being distorted.
+Otherwise img is returned as it is, as well as when dimensions aren't used.
+*/
+func coverCrop(img image.Image, dimensions []int, full bool, opts PixelOptions) image.Image {
+
+	b := img.Bounds()
+	if opts.Fit != "cover" || len(dimensions) != 2 || full || b.Empty() || dimensions[0] < 1 || dimensions[1] < 1 {
+		return img
+	}
+
+	// Width to height ratio of the box in pixels of the source image
+	boxRatio := float64(dimensions[0]) / (float64(dimensions[1]) * fontRatioOf(opts))
+
+	cropWidth, cropHeight := b.Dx(), b.Dy()
+	if float64(cropWidth)/float64(cropHeight) > boxRatio {
+		cropWidth = maxInt(int(float64(cropHeight)*boxRatio+0.5), 1)
+	} else {
+		cropHeight = maxInt(int(float64(cropWidth)/boxRatio+0.5), 1)
+	}
+
+	x := b.Min.X + (b.Dx()-cropWidth)/2
+	y := b.Min.Y + (b.Dy()-cropHeight)/2
+
+	return imaging.Crop(img, image.Rect(x, y, x+cropWidth, y+cropHeight))
+}
+
+/*
+Pads asciiSet with fill on each side so it's centered in a box of boxWidth x boxHeight characters, such as when
+PixelOptions.Fit is "contain" and the image doesn't fill the box it's sized for. If bg is set, padding is drawn
+with it as its background color so colored ascii art is framed consistently. When the padding can't be split
+evenly, the extra column or row goes to the right or bottom.
+*/
+func PadAsciiChars(asciiSet [][]AsciiChar, boxWidth, boxHeight int, fill string, bg *[3]int, opts CharOptions) [][]AsciiChar {
+
+	opts = resolveColorMode(opts)
+
+	if len(asciiSet) == 0 || (len(asciiSet[0]) >= boxWidth && len(asciiSet) >= boxHeight) {
+		return asciiSet
+	}
+
+	padChar := AsciiChar{Simple: fill, OriginalColor: fill, SetColor: fill}
+	if bg != nil {
+		padChar.OriginalColor = colorText(fill, *bg, true, opts)
+		padChar.SetColor = padChar.OriginalColor
+		padChar.RgbValue = [3]uint32{uint32(bg[0]), uint32(bg[1]), uint32(bg[2])}
+		padChar.BgRgbValue = padChar.RgbValue
+	}
+
+	width := maxInt(len(asciiSet[0]), boxWidth)
+	left := (width - len(asciiSet[0])) / 2
+	top := (maxInt(len(asciiSet), boxHeight) - len(asciiSet)) / 2
+	bottom := maxInt(len(asciiSet), boxHeight) - len(asciiSet) - top
+
+	padRow := func() []AsciiChar {
+		row := make([]AsciiChar, width)
+		for i := range row {
+			row[i] = padChar
+		}
+		return row
+	}
+
+	result := make([][]AsciiChar, 0, top+len(asciiSet)+bottom)
+
+	for i := 0; i < top; i++ {
+		result = append(result, padRow())
+	}
+
+	for _, line := range asciiSet {
+		row := padRow()
+		copy(row[left:], line)
+		result = append(result, row)
+	}
+
+	for i := 0; i < bottom; i++ {
+		result = append(result, padRow())
+	}
+
+	return result
+}
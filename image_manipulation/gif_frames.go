@@ -0,0 +1,134 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+)
+
+// Decodes all frames of the gif in r and returns them the same way as ExtractGifFrames()
+func DecodeGifFrames(r io.Reader) ([]image.Image, []int, error) {
+	g, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	frames, delays := ExtractGifFrames(g)
+	return frames, delays, nil
+}
+
+/*
+Returns each frame of g as it would be displayed, along with its delay in 100ths of a second. Gif frames may only
+cover part of the image and leave pixels of the transparent index untouched, so each frame is drawn over the
+previous ones on a canvas of the gif's full size. The canvas is then disposed of according to the frame's disposal
+method, either by clearing the frame's area to transparent or restoring it to how it was before the frame was drawn.
+
+Each returned frame is a separate copy that can be passed to ConvertToAsciiPixels().
+*/
+func ExtractGifFrames(g *gif.GIF) ([]image.Image, []int) {
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+
+	// Gifs from some encoders don't set the logical screen size, so it's covered by the frames instead
+	if bounds.Empty() {
+		for _, frame := range g.Image {
+			bounds = bounds.Union(frame.Rect)
+		}
+	}
+
+	canvas := image.NewRGBA(bounds)
+
+	frames := make([]image.Image, len(g.Image))
+	delays := make([]int, len(g.Image))
+
+	for i, frame := range g.Image {
+
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = copyRGBA(canvas)
+		}
+
+		drawPalettedOver(canvas, frame)
+
+		frames[i] = copyRGBA(canvas)
+		if i < len(g.Delay) {
+			delays[i] = g.Delay[i]
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Rect, image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return frames, delays
+}
+
+/*
+Draws frame over canvas with the same result as draw.Draw() with draw.Over. Instead of converting the color of each
+pixel through its palette, the palette's alpha-premultiplied values are computed once and indexed per pixel, since gif
+frames rarely have more than 256 colors but usually have many pixels. Indices outside the palette are left transparent.
+
+This only speeds up compositing gif frames here. Library callers that pass an *image.Paletted to
+ConvertToAsciiPixels() get no fast path for it, since resizing converts it to an *image.NRGBA before its pixels are
+sampled.
+*/
+func drawPalettedOver(canvas *image.RGBA, frame *image.Paletted) {
+
+	colors := make([][4]uint32, len(frame.Palette))
+	for i, c := range frame.Palette {
+		r, g, b, a := c.RGBA()
+		colors[i] = [4]uint32{r, g, b, a}
+	}
+
+	bounds := frame.Rect.Intersect(canvas.Rect)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		src := frame.Pix[frame.PixOffset(bounds.Min.X, y):]
+		dst := canvas.Pix[canvas.PixOffset(bounds.Min.X, y):]
+
+		for x := 0; x < bounds.Dx(); x++ {
+			index := int(src[x])
+			if index >= len(colors) || colors[index][3] == 0 {
+				continue
+			}
+			c := colors[index]
+
+			d := dst[x*4 : x*4+4 : x*4+4]
+			a := (0xffff - c[3]) * 0x101
+			for i := 0; i < 4; i++ {
+				d[i] = uint8((uint32(d[i])*a/0xffff + c[i]) >> 8)
+			}
+		}
+	}
+}
+
+func copyRGBA(img *image.RGBA) *image.RGBA {
+	copied := image.NewRGBA(img.Rect)
+	copy(copied.Pix, img.Pix)
+	return copied
+}
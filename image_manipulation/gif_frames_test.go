@@ -0,0 +1,107 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"testing"
+)
+
+// Returns a frame covering rect whose palette has opaque, semi-transparent and fully transparent colors
+func palettedFrame(rect image.Rectangle, seed int) *image.Paletted {
+	palette := color.Palette{color.NRGBA{}}
+	for i := 1; i < 64; i++ {
+		palette = append(palette, color.NRGBA{uint8(i*37 + seed), uint8(i*11 + seed*3), uint8(i * 5), uint8(i*53 + seed)})
+	}
+	palette = append(palette, color.NRGBA{200, 10, 90, 255})
+
+	frame := image.NewPaletted(rect, palette)
+	for i := range frame.Pix {
+		frame.Pix[i] = uint8((i*7 + seed*13) % len(palette))
+	}
+	return frame
+}
+
+// Composites g the way ExtractGifFrames() does, but with draw.Draw() in place of drawPalettedOver()
+func drawDrawFrames(g *gif.GIF) []*image.RGBA {
+	canvas := image.NewRGBA(image.Rect(0, 0, g.Config.Width, g.Config.Height))
+	frames := make([]*image.RGBA, len(g.Image))
+
+	for i, frame := range g.Image {
+		var previous *image.RGBA
+		if g.Disposal[i] == gif.DisposalPrevious {
+			previous = copyRGBA(canvas)
+		}
+
+		draw.Draw(canvas, frame.Rect, frame, frame.Rect.Min, draw.Over)
+		frames[i] = copyRGBA(canvas)
+
+		switch g.Disposal[i] {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Rect, image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	return frames
+}
+
+func TestDrawPalettedOverMatchesDrawDraw(t *testing.T) {
+	// Frames overlap each other, and the last one reaches past the canvas, with every disposal method in between
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			palettedFrame(image.Rect(0, 0, 40, 30), 0),
+			palettedFrame(image.Rect(5, 3, 30, 25), 1),
+			palettedFrame(image.Rect(10, 8, 35, 28), 2),
+			palettedFrame(image.Rect(2, 2, 20, 20), 3),
+			palettedFrame(image.Rect(25, 15, 60, 45), 4),
+		},
+		Delay:    []int{1, 2, 3, 4, 5},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalPrevious, gif.DisposalNone, gif.DisposalBackground},
+		Config:   image.Config{Width: 40, Height: 30},
+	}
+
+	want := drawDrawFrames(g)
+	got, _ := ExtractGifFrames(g)
+
+	for i := range want {
+		gotFrame := got[i].(*image.RGBA)
+		if string(gotFrame.Pix) != string(want[i].Pix) {
+			t.Errorf("frame %d differs from draw.Draw() with draw.Over", i)
+		}
+	}
+}
+
+func BenchmarkDrawPalettedOver(b *testing.B) {
+	frame := palettedFrame(image.Rect(0, 0, 640, 480), 0)
+	canvas := image.NewRGBA(frame.Rect)
+
+	b.Run("drawPalettedOver", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			drawPalettedOver(canvas, frame)
+		}
+	})
+
+	b.Run("draw.Draw", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			draw.Draw(canvas, frame.Rect, frame, frame.Rect.Min, draw.Over)
+		}
+	})
+}
@@ -17,9 +17,13 @@ limitations under the License.
 package image_conversions
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
+	"math"
+	"runtime"
+	"sync"
 
 	"github.com/TheZoraiz/ascii-image-converter/aic_package/winsize"
 	"github.com/disintegration/imaging"
@@ -29,10 +33,314 @@ type AsciiPixel struct {
 	charDepth      uint32
 	grayscaleValue [3]uint32
 	rgbValue       [3]uint32
+
+	// From 0 for fully transparent to 255 for opaque
+	alpha uint32
+
+	// 16-bit version of charDepth, only valid while hasDepth16 is set. newAsciiPixel() sets both when
+	// PixelOptions.HighPrecision is set, and every later step that changes charDepth either updates depth16 as well
+	// or clears hasDepth16
+	depth16    uint32
+	hasDepth16 bool
+
+	// Only set when PixelOptions.EdgeMode is set
+	isEdge    bool
+	edgeAngle float64
+}
+
+// Optional adjustments applied to the image before and after it's resized, before its AsciiPixel instances
+// are returned. The zero value leaves the image untouched
+type PixelOptions struct {
+	// Region of the image, relative to its top left corner, that's converted instead of the whole image.
+	// It's applied before resizing, and must lie within the image. The whole image is used when empty
+	Crop image.Rectangle
+
+	// Trim rows and columns of near-uniform color around the edges of the image after it's cropped, such as
+	// margins of screenshots and scans. The color of the top left pixel is trimmed away
+	Trim bool
+
+	// Largest difference, from 0 to 255, any channel of a pixel can have from the trimmed color for it to
+	// still count as part of the margin when Trim is set. Only exact matches are trimmed when set to 0
+	TrimTolerance int
+
+	// Angle in degrees by which the image is rotated clockwise after it's cropped and trimmed. The image is enlarged
+	// to fit the rotated one, unless the angle is a multiple of 90
+	Rotate float64
+
+	// Color used to fill the corners exposed by Rotate. Defaults to black when nil
+	RotateBackground color.Color
+
+	// Sigma of the Gaussian blur applied to the full resolution image after it's rotated, which reduces noise
+	// that would otherwise turn into speckles. Left unblurred when set to 0
+	Blur float64
+
+	// Sigma of the unsharp mask applied to the full resolution image after Blur, which keeps edges of logos and
+	// text crisp once the image is shrunk. Higher values sharpen more. Left unsharpened when set to 0
+	Sharpen float64
+
+	// Color that transparent pixels are blended over before their grayscale and RGB values are taken.
+	// Their alpha is still kept for CharOptions.AlphaThreshold. Defaults to black when nil
+	Background color.Color
+
+	// Take charDepth from each pixel's own grayscale value multiplied by its alpha, instead of from its color after
+	// it's blended over Background. Semi-transparent edges then fade towards sparse characters whatever Background
+	// is, so anti-aliased logos get soft edges. Grayscale and RGB values are still blended over Background
+	AlphaWeighted bool
+
+	// Blend transparent pixels over a checkerboard instead of Background, like image editors do, to show which
+	// parts of the image are transparent. Squares are CheckerSize pixels of the resized image wide and tall,
+	// or 4 when it's 0, and alternate between CheckerColors, which default to dark and light gray when nil
+	Checkerboard  bool
+	CheckerSize   int
+	CheckerColors [2]color.Color
+
+	// Strength of dithering on grayscale values, between 0.0 and 1.0.
+	// Dithering is disabled when set to 0
+	Dithering float64
+
+	// Algorithm used for dithering. Either "floyd-steinberg", "atkinson" or "bayer".
+	// Defaults to "floyd-steinberg" when empty
+	DitherMode string
+
+	// Size of the Bayer matrix when DitherMode is "bayer". Either 2, 4 or 8.
+	// Defaults to 4 when set to 0
+	BayerSize int
+
+	// Resize the image to twice the height so each character can represent two vertical pixels.
+	// The returned slice should be passed to ConvertToHalfBlockChars(). Ignored if isBraille is true
+	HalfBlock bool
+
+	// Resize the image to twice the width and height so each character can represent a 2x2 block.
+	// The returned slice should be passed to ConvertToQuadrantChars(). Ignored if isBraille is true
+	Quadrant bool
+
+	// Resize the image to twice the width and three times the height so each character can represent a 2x3 block.
+	// The returned slice should be passed to ConvertToSextantChars(). Ignored if isBraille is true, and overrides Quadrant
+	Sextant bool
+
+	// Resize the image to twice the width and four times the height so each character can represent a 2x4 block,
+	// like braille characters do. The returned slice should be passed to ConvertToOctantChars(). Ignored if isBraille
+	// is true, and overrides Sextant and Quadrant
+	Octant bool
+
+	// Dots per braille character when isBraille is true. Either 8 for 2x4 dots, or 6 for 2x3 dots, which suits
+	// fonts that cramp the bottom row. The same value should be set in CharOptions.BrailleDots for
+	// ConvertToBrailleChars(). Defaults to 8 when set to 0
+	BrailleDots int
+
+	// Number of levels grayscale values are quantized to while dithering. This should be the
+	// number of characters that will be mapped against, or 2 for braille art
+	DitherLevels int
+
+	// Formula grayscale values are calculated with from RGB values. Either "rec601", "rec709" or "average",
+	// which weighs each channel equally. Uses color.GrayModel when empty
+	Luminance string
+
+	// Custom red, green and blue weights for grayscale values, overriding Luminance. They're normalized to
+	// sum to 1 and can't be negative. Unused when all of them are 0
+	LuminanceWeights [3]float64
+
+	// Keep the full 16-bit precision of grayscale values while resizing, so images with 16-bit channels give
+	// smoother ramps when mapped against long character sets by ConvertToAsciiChars(). Grayscale and RGB values
+	// stay 8-bit. Steps that change grayscale values, such as adjustments and dithering, drop the extra precision
+	// of pixels they change. Slower, so it's off by default
+	HighPrecision bool
+
+	// Resize the image in linear light instead of sRGB, so fine bright details aren't darkened by averaging
+	// pixels whose values are gamma encoded. Pixels are converted to linear light and back through 16-bit
+	// values around the resize. Slower, so it's off by default
+	LinearResize bool
+
+	// Invert RGB and grayscale values of each pixel as a photo negative, before equalization and every other
+	// adjustment so those apply to the inverted image. Unlike the negative parameter of ConvertToAsciiChars(),
+	// which inverts after adjustments, this changes the pixels that edges, braille dots and blocks are found from
+	Negative bool
+
+	// Spread grayscale values across the full 0-255 range through histogram equalization, before any other
+	// adjustment. RGB values are left untouched. With ConvertToAsciiPixelTiles(), each strip is equalized separately
+	Equalize bool
+
+	// Gamma correction applied to grayscale values before characters are mapped, where values
+	// above 1 brighten shadows and values below 1 darken them. Treated as 1 when set to 0
+	Gamma float64
+
+	// Shift applied to grayscale and RGB values after gamma correction, between -100 and 100
+	// percent of the full 0-255 range. Left unchanged when set to 0
+	Brightness float64
+
+	// Factor by which grayscale and RGB values are scaled around the midpoint 128 after brightness
+	// is applied. Values above 1 increase contrast. Treated as 1 when set to 0
+	Contrast float64
+
+	// Factor by which the saturation of RGB values is scaled, leaving grayscale values untouched.
+	// Values below 1 desaturate colors. Treated as 1 when set to 0
+	Saturation float64
+
+	// Blend RGB values towards the classic sepia tone by this percentage between 0 and 100, after saturation is
+	// scaled. Grayscale values are left untouched. Left unchanged when set to 0
+	Sepia float64
+
+	// Number of levels, from 2 to 256, that each grayscale and RGB channel is quantized to after adjustments,
+	// for a bold poster-like look. 256 leaves values unchanged, and it's disabled when set to 0
+	Posterize int
+
+	// Colors that RGB values are replaced with after adjustments and posterization, interpolated from GradientStart
+	// for the darkest pixels to GradientEnd for the brightest ones. Disabled unless both are set
+	GradientStart, GradientEnd color.Color
+
+	// Replace RGB values with hues cycling across the image instead, overriding GradientStart and GradientEnd.
+	// Hues vary by x position for "column", by y position for "row", or by grayscale value for "brightness".
+	// With ConvertToAsciiPixelTiles(), "row" hues cycle within each strip. Disabled when empty
+	Rainbow string
+
+	// Reduce RGB values to at most this many colors, from 2 to 256, after colors are replaced by Rainbow or the
+	// gradient. Fewer colors give a flatter look, and fewer color changes in terminal output and saved files.
+	// Grayscale values are left untouched. With ConvertToAsciiPixelTiles(), each strip gets its own palette.
+	// Disabled when set to 0
+	Quantize int
+
+	// Algorithm that generates the palette for Quantize. Either "median-cut", which splits colors into boxes
+	// at their medians, or "k-means", which refines the median cut palette further at some extra cost.
+	// Defaults to "median-cut" when empty
+	QuantizeMethod string
+
+	// Name of a palette, either "cga", "gameboy", "c64", "nes" or one added with RegisterPalette(), that RGB values
+	// are mapped to the nearest colors of after Quantize, for a retro console look. Disabled when empty
+	Palette string
+
+	// Colors RGB values are mapped to instead of the palette named by Palette, with red, green and blue values from
+	// 0 to 255. Unused when nil
+	PaletteColors [][3]int
+
+	// Metric used to find the nearest colors of Palette or PaletteColors, with the same values as
+	// CharOptions.ColorDistance. Defaults to ColorDistanceCIEDE2000 when empty
+	PaletteDistance string
+
+	// Edge detection algorithm run on grayscale values after adjustments, marking pixels for ConvertToEdgeChars().
+	// Either "sobel", "canny" or empty for no edge detection
+	EdgeMode string
+
+	// Gradient magnitude, between 0 and 255, a pixel needs to be marked as an edge. For "canny", this is the
+	// high threshold of hysteresis. Defaults to 64 when set to 0
+	EdgeThreshold int
+
+	// Gradient magnitude, between 0 and EdgeThreshold, a pixel connected to an edge needs to be marked
+	// as one too. Only used by "canny". Defaults to half of EdgeThreshold when set to 0
+	EdgeLowThreshold int
+
+	// Sigma of the Gaussian blur applied before finding gradients. Only used by "canny".
+	// Defaults to 1.4 when set to 0
+	EdgeBlur float64
+
+	// Resampling filter used to shrink the image. Either "nearest", "box", "linear", "catmull-rom" or "lanczos".
+	// Defaults to "lanczos" when empty
+	ResizeFilter string
+
+	// Maximum number of goroutines pixels are sampled with, e.g. to stay within CPU limits of containers.
+	// Defaults to GOMAXPROCS when set to 0
+	MaxConcurrency int
+
+	// Height of a terminal character divided by its width, used to keep the image's aspect ratio
+	// when only one dimension is known. Defaults to 2 when set to 0
+	FontRatio float64
+
+	// Skip aspect ratio correction for terminal fonts, overriding FontRatio with 1, so each character matches
+	// a square of the image. For output that's rendered with square cells, such as exported images or HTML
+	// with an adjusted line height
+	NoAspectCorrection bool
+
+	// How an image is fitted to dimensions that are both set. Either "stretch", which resizes it to exactly those
+	// and distorts its aspect ratio, "contain", which keeps its aspect ratio within them, or "cover", which fills
+	// them and crops what's left over equally from both sides. Defaults to "stretch" when empty
+	Fit string
+
+	// Size of the terminal ascii art is fitted to. The terminal is only queried for the ones set to 0
+	TerminalWidth, TerminalHeight int
+}
+
+// Resampling filters selectable through PixelOptions.ResizeFilter
+var resizeFilters = map[string]imaging.ResampleFilter{
+	"nearest":     imaging.NearestNeighbor,
+	"box":         imaging.Box,
+	"linear":      imaging.Linear,
+	"catmull-rom": imaging.CatmullRom,
+	"lanczos":     imaging.Lanczos,
+}
+
+// Returns the resampling filter set in opts.ResizeFilter
+func ResizeFilter(opts PixelOptions) (imaging.ResampleFilter, error) {
+	if opts.ResizeFilter == "" {
+		return imaging.Lanczos, nil
+	}
+
+	filter, ok := resizeFilters[opts.ResizeFilter]
+	if !ok {
+		return imaging.ResampleFilter{}, fmt.Errorf("unknown resize filter %q", opts.ResizeFilter)
+	}
+
+	return filter, nil
+}
+
+// Dimensions of the ascii art returned by ConvertToAsciiPixelsWithOptions()
+type AsciiSize struct {
+	// Number of characters in each row and column of the ascii art
+	Width, Height int
+
+	// Number of AsciiPixel instances in each row and column of the returned slice. These are larger than Width
+	// and Height for braille, quadrant and half block art, where a single character represents multiple pixels
+	PixelWidth, PixelHeight int
+}
+
+// Returns the AsciiSize of an imgSet with passed dimensions, undoing resizeForSubPixels()
+func asciiSize(pixelWidth, pixelHeight int, isBraille bool, opts PixelOptions) AsciiSize {
+	size := AsciiSize{
+		Width:       pixelWidth,
+		Height:      pixelHeight,
+		PixelWidth:  pixelWidth,
+		PixelHeight: pixelHeight,
+	}
+
+	if isBraille {
+		size.Width, size.Height = pixelWidth/2, pixelHeight/brailleRows(opts.BrailleDots)
+	} else if opts.Octant {
+		size.Width, size.Height = pixelWidth/2, pixelHeight/4
+	} else if opts.Sextant {
+		size.Width, size.Height = pixelWidth/2, pixelHeight/3
+	} else if opts.Quadrant {
+		size.Width, size.Height = pixelWidth/2, pixelHeight/2
+	} else if opts.HalfBlock {
+		size.Height = (pixelHeight + 1) / 2
+	}
+
+	return size
 }
 
-func resizeForBraille(asciiWidth, asciiHeight int) (int, int) {
-	return asciiWidth * 2, asciiHeight * 4
+/*
+Returns dimensions of the resized image, depending on how many of its pixels make up a single character.
+
+Since braille art is resized to 2x4 pixels per character, or 2x3 with 6 dots, the threshold passed to ConvertToBrailleChars() is compared
+against each of those pixels individually rather than an average of the character, so thin details survive as single
+dots. When the image is smaller than its braille dimensions, it's upsampled with the resize filter, which blurs edges
+across neighboring dots. In that case the threshold decides where along the blur a shape's edge lands, and pairing it
+with dithering keeps gradients from collapsing into solid blocks of dots.
+*/
+func resizeForSubPixels(asciiWidth, asciiHeight int, isBraille bool, opts PixelOptions) (int, int) {
+	if isBraille {
+		return asciiWidth * 2, asciiHeight * brailleRows(opts.BrailleDots)
+	}
+	if opts.Octant {
+		return asciiWidth * 2, asciiHeight * 4
+	}
+	if opts.Sextant {
+		return asciiWidth * 2, asciiHeight * 3
+	}
+	if opts.Quadrant {
+		return asciiWidth * 2, asciiHeight * 2
+	}
+	if opts.HalfBlock {
+		return asciiWidth, asciiHeight * 2
+	}
+	return asciiWidth, asciiHeight
 }
 
 /*
@@ -42,163 +350,533 @@ instance to simplify getting numeric data for ASCII character comparison.
 
 The returned 2D AsciiPixel slice contains each corresponding pixel's values. Grayscale value
 ranges from 0 to 65535, while RGB values are separate.
+
+ConvertToAsciiPixelsWithOptions() takes further settings and also returns the size of the ascii art.
 */
 func ConvertToAsciiPixels(img image.Image, dimensions []int, width, height int, flipX, flipY, full, isBraille bool) ([][]AsciiPixel, error) {
+	imgSet, _, err := ConvertToAsciiPixelsWithOptions(img, dimensions, width, height, flipX, flipY, full, isBraille, PixelOptions{})
+	return imgSet, err
+}
 
-	var asciiWidth, asciiHeight int
-	var smallImg image.Image
+/*
+Same as ConvertToAsciiPixels(), except that opts are applied as well. The returned AsciiSize holds the dimensions
+of the eventual ascii art, both in characters and in pixels of the slice.
 
-	terminalWidth, terminalHeight, err := winsize.GetTerminalSize()
+Steps are applied in the following order, each only if its options are set:
+
+ 1. opts.Crop, opts.Trim, opts.Rotate, opts.Blur and opts.Sharpen, before the image is resized
+ 2. opts.Negative, which inverts values
+ 3. opts.Equalize, which equalizes grayscale values
+ 4. opts.Gamma, opts.Brightness, opts.Contrast and opts.Saturation, which adjust values, then opts.Posterize
+ 5. opts.Rainbow, or opts.GradientStart and opts.GradientEnd, which replace colors
+ 6. opts.Quantize, which reduces colors to fewer of them
+ 7. opts.Palette or opts.PaletteColors, which map colors to a palette
+ 8. opts.EdgeMode, which detects edges on the adjusted grayscale values
+ 9. opts.Dithering, which dithers grayscale values
+
+For braille art, these happen on the upsampled image so each dot is handled individually.
+
+Convert() wraps this function and the character conversions with options, which is simpler for most uses.
+*/
+func ConvertToAsciiPixelsWithOptions(img image.Image, dimensions []int, width, height int, flipX, flipY, full, isBraille bool, opts PixelOptions) ([][]AsciiPixel, AsciiSize, error) {
+	return ConvertToAsciiPixelsContext(context.Background(), img, dimensions, width, height, flipX, flipY, full, isBraille, opts)
+}
+
+/*
+Same as ConvertToAsciiPixelsWithOptions(), except that ctx is checked after each row of pixels as well as before
+each further step, returning ctx.Err() early if it's cancelled. Useful for large images on servers, where the
+client may disconnect before conversion is done.
+*/
+func ConvertToAsciiPixelsContext(ctx context.Context, img image.Image, dimensions []int, width, height int, flipX, flipY, full, isBraille bool, opts PixelOptions) ([][]AsciiPixel, AsciiSize, error) {
+	return convertToAsciiPixels(ctx, img, dimensions, width, height, flipX, flipY, full, isBraille, opts, nil)
+}
+
+// Same as ConvertToAsciiPixelsContext(), except that pixels are sampled into buf if it has the right size, as done by Converter
+func convertToAsciiPixels(ctx context.Context, img image.Image, dimensions []int, width, height int, flipX, flipY, full, isBraille bool, opts PixelOptions, buf [][]AsciiPixel) ([][]AsciiPixel, AsciiSize, error) {
+
+	if err := checkDitherOptions(opts); err != nil {
+		return nil, AsciiSize{}, err
+	}
+	if err := checkAdjustOptions(opts); err != nil {
+		return nil, AsciiSize{}, err
+	}
+	if err := checkEdgeOptions(opts); err != nil {
+		return nil, AsciiSize{}, err
+	}
+	if err := checkLuminanceOptions(opts); err != nil {
+		return nil, AsciiSize{}, err
+	}
+	if err := checkColorEffectOptions(opts); err != nil {
+		return nil, AsciiSize{}, err
+	}
+	if err := checkQuantizeOptions(opts); err != nil {
+		return nil, AsciiSize{}, err
+	}
+	if err := checkPaletteOptions(opts); err != nil {
+		return nil, AsciiSize{}, err
+	}
+
+	img, err := TransformImage(img, opts)
 	if err != nil {
+		return nil, AsciiSize{}, err
+	}
+
+	smallImg, err := ResizeImage(img, dimensions, width, height, full, isBraille, opts)
+	if err != nil {
+		return nil, AsciiSize{}, err
+	}
+
+	b := smallImg.Bounds()
+
+	imgSet, err := samplePixels(ctx, smallImg, opts, buf)
+	if err != nil {
+		return nil, AsciiSize{}, err
+	}
+
+	imgSet, err = processPixels(ctx, imgSet, flipX, flipY, opts)
+	if err != nil {
+		return nil, AsciiSize{}, err
+	}
+
+	return imgSet, asciiSize(b.Dx(), b.Dy(), isBraille, opts), nil
+}
+
+// Runs inversion, equalization, adjustments, posterization, color effects, quantization, palette mapping, edge detection, dithering and flipping on sampled pixels, in that order
+func processPixels(ctx context.Context, imgSet [][]AsciiPixel, flipX, flipY bool, opts PixelOptions) ([][]AsciiPixel, error) {
+
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	if full {
-		asciiWidth = terminalWidth - 1
+	if opts.Negative {
+		invertImgSet(imgSet)
+	}
 
-		// Passing 0 in place of width keeps the original image's aspect ratio
-		smallImg = imaging.Resize(img, asciiWidth, 0, imaging.Lanczos)
-		asciiHeight = smallImg.Bounds().Max.Y - smallImg.Bounds().Min.Y
+	if opts.Equalize {
+		equalizeImgSet(imgSet)
+	}
 
-		// To fix aspect ratio in eventual ascii art
-		asciiHeight = int(0.5 * float64(asciiHeight))
+	if hasAdjustments(opts) {
+		adjustImgSet(imgSet, opts)
+	}
+
+	if opts.Posterize > 0 && opts.Posterize < 256 {
+		posterizeImgSet(imgSet, opts.Posterize)
+	}
+
+	if opts.Rainbow != "" {
+		rainbowImgSet(imgSet, opts.Rainbow)
+	} else if opts.GradientStart != nil && opts.GradientEnd != nil {
+		gradientImgSet(imgSet, opts.GradientStart, opts.GradientEnd)
+	}
+
+	if opts.Quantize > 0 {
+		quantizeImgSet(imgSet, opts.Quantize, opts.QuantizeMethod)
+	}
+
+	if palette := selectedPalette(opts); palette != nil {
+		paletteImgSet(imgSet, palette, opts.PaletteDistance)
+	}
+
+	switch opts.EdgeMode {
+	case "sobel":
+		sobelEdges(imgSet, opts.EdgeThreshold)
+	case "canny":
+		cannyEdges(imgSet, opts.EdgeLowThreshold, opts.EdgeThreshold, opts.EdgeBlur)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.Dithering > 0 {
+		ditherImgSet(imgSet, opts)
+	}
+
+	// This rarely affects performance since the ascii art 2D slice size isn't that large
+	if flipX || flipY {
+		imgSet = reverse(imgSet, flipX, flipY)
 
-		if isBraille {
-			asciiWidth, asciiHeight = resizeForBraille(asciiWidth, asciiHeight)
+		if opts.EdgeMode != "" {
+			mirrorEdgeAngles(imgSet, flipX, flipY)
 		}
-		smallImg = imaging.Resize(img, asciiWidth, asciiHeight, imaging.Lanczos)
+	}
 
-	} else if (width != 0 || height != 0) && len(dimensions) == 0 {
-		// If either width or height is set and dimensions aren't given
+	return imgSet, nil
+}
+
+/*
+Gets an AsciiPixel instance for each pixel of passed image. Since pixels are independent of each other, rows are
+split into contiguous chunks that are sampled concurrently, one chunk per goroutine up to opts.MaxConcurrency, and
+each row is written to its own index so the order of imgSet is kept. ctx is checked before each row.
+
+If buf has as many rows as the image, they're reused instead of allocating new ones.
+*/
+func samplePixels(ctx context.Context, img image.Image, opts PixelOptions, buf [][]AsciiPixel) ([][]AsciiPixel, error) {
+
+	b := img.Bounds()
+	imgSet := buf
+	if len(imgSet) != b.Dy() {
+		imgSet = make([][]AsciiPixel, b.Dy())
+	}
+
+	workers := opts.MaxConcurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(imgSet) {
+		workers = len(imgSet)
+	}
+	if workers < 1 {
+		return imgSet, nil
+	}
+	chunkSize := (len(imgSet) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+
+	for start := 0; start < len(imgSet); start += chunkSize {
+		end := start + chunkSize
+		if end > len(imgSet) {
+			end = len(imgSet)
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+
+			for row := start; row < end; row++ {
+				if ctx.Err() != nil {
+					return
+				}
+				imgSet[row] = samplePixelRow(img, b.Min.Y+row, opts, imgSet[row])
+			}
+		}(start, end)
+	}
 
-		if width > terminalWidth-1 {
-			return nil, fmt.Errorf("set width must be lower than terminal width")
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return imgSet, nil
+}
+
+/*
+Returns AsciiPixel instances for row y of passed image. The *image.NRGBA returned by imaging.Resize() and the
+*image.RGBA64 returned with opts.HighPrecision, as well as *image.RGBA and *image.Gray, have their Pix slices
+read directly, since calling At() for every pixel goes through interface dispatch and allocates a color.Color
+each time. Other image types fall back to At(), with the same end result. The row is written into temp if it has
+enough capacity.
+*/
+func samplePixelRow(img image.Image, y int, opts PixelOptions, temp []AsciiPixel) []AsciiPixel {
+
+	b := img.Bounds()
+	if cap(temp) < b.Dx() {
+		temp = make([]AsciiPixel, b.Dx())
+	}
+	temp = temp[:b.Dx()]
+
+	// Position of the row in the image, for backgroundAt()
+	rowIndex := y - b.Min.Y
+
+	switch src := img.(type) {
+	case *image.NRGBA:
+		row := src.Pix[src.PixOffset(b.Min.X, y):]
+		for x := range temp {
+			i := x * 4
+			// Same conversion as color.NRGBA.RGBA()
+			a := uint32(row[i+3])
+			r := uint32(row[i]) * 0x101 * a / 0xff
+			g := uint32(row[i+1]) * 0x101 * a / 0xff
+			bl := uint32(row[i+2]) * 0x101 * a / 0xff
+			temp[x] = newAsciiPixel(r, g, bl, a*0x101, backgroundAt(x, rowIndex, opts), opts)
+		}
+
+	case *image.RGBA:
+		row := src.Pix[src.PixOffset(b.Min.X, y):]
+		for x := range temp {
+			i := x * 4
+			temp[x] = newAsciiPixel(uint32(row[i])*0x101, uint32(row[i+1])*0x101, uint32(row[i+2])*0x101, uint32(row[i+3])*0x101, backgroundAt(x, rowIndex, opts), opts)
+		}
+
+	case *image.Gray:
+		row := src.Pix[src.PixOffset(b.Min.X, y):]
+		for x := range temp {
+			v := uint32(row[x]) * 0x101
+			temp[x] = newAsciiPixel(v, v, v, 0xffff, backgroundAt(x, rowIndex, opts), opts)
 		}
 
+	case *image.RGBA64:
+		row := src.Pix[src.PixOffset(b.Min.X, y):]
+		for x := range temp {
+			i := x * 8
+			temp[x] = newAsciiPixel(
+				uint32(row[i])<<8|uint32(row[i+1]),
+				uint32(row[i+2])<<8|uint32(row[i+3]),
+				uint32(row[i+4])<<8|uint32(row[i+5]),
+				uint32(row[i+6])<<8|uint32(row[i+7]),
+				backgroundAt(x, rowIndex, opts),
+				opts,
+			)
+		}
+
+	default:
+		for x := range temp {
+			r, g, bl, a := img.At(b.Min.X+x, y).RGBA()
+			temp[x] = newAsciiPixel(r, g, bl, a, backgroundAt(x, rowIndex, opts), opts)
+		}
+	}
+
+	return temp
+}
+
+/*
+Returns an AsciiPixel for a pixel with passed alpha-premultiplied 16-bit channels. Transparent pixels are blended over
+background, so their color values are opaque from here on. Since the channels are premultiplied, only the
+background's share needs to be added, and a nil background is treated as black.
+*/
+func newAsciiPixel(r, g, b, a uint32, background color.Color, opts PixelOptions) AsciiPixel {
+
+	// Luminance of premultiplied channels is the pixel's own luminance weighted by its alpha
+	var weighted uint32
+	if opts.AlphaWeighted {
+		weighted = luminance(r, g, b, opts)
+	}
+
+	if background != nil && a < 0xffff {
+		br, bg, bb, _ := background.RGBA()
+		// Premultiplied channels are at most a, so blended ones can't exceed 0xffff
+		r = r + br*(0xffff-a)/0xffff
+		g = g + bg*(0xffff-a)/0xffff
+		b = b + bb*(0xffff-a)/0xffff
+	}
+
+	// Grayscale pixels have the same value for each channel, so it's used for charDepth as well
+	gray := luminance(r, g, b, opts)
+	depth := gray
+	if opts.AlphaWeighted {
+		depth = weighted
+	}
+
+	var depth16 uint32
+	if opts.HighPrecision {
+		depth16 = depth
+	}
+
+	return AsciiPixel{
+		charDepth:      depth / 257,
+		grayscaleValue: [3]uint32{gray / 257, gray / 257, gray / 257},
+		rgbValue:       [3]uint32{r / 257, g / 257, b / 257},
+		alpha:          a / 257,
+		depth16:        depth16,
+		hasDepth16:     opts.HighPrecision,
+	}
+}
+
+/*
+Shrinks the passed image according to passed dimensions or terminal size if none are passed, the same way
+ConvertToAsciiPixels() does. Without braille or block characters, each pixel of the returned image corresponds
+to a single character of the would-be ascii art.
+*/
+func ResizeImage(img image.Image, dimensions []int, width, height int, full, isBraille bool, opts PixelOptions) (image.Image, error) {
+
+	filter, err := ResizeFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	img = coverCrop(img, dimensions, full, opts)
+
+	pixelWidth, pixelHeight, err := resizeDimensions(img.Bounds(), dimensions, width, height, full, isBraille, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return resizeWithFilter(img, pixelWidth, pixelHeight, filter, opts), nil
+}
+
+// Returns the dimensions ResizeImage() shrinks an image of passed bounds to
+func resizeDimensions(bounds image.Rectangle, dimensions []int, width, height int, full, isBraille bool, opts PixelOptions) (int, int, error) {
+
+	var asciiWidth, asciiHeight int
+
+	if opts.FontRatio < 0 {
+		return 0, 0, fmt.Errorf("%w, got %v", ErrInvalidFontRatio, opts.FontRatio)
+	}
+	if err := checkFitOptions(opts); err != nil {
+		return 0, 0, err
+	}
+	if err := checkDimensions(dimensions); err != nil {
+		return 0, 0, err
+	}
+	if opts.BrailleDots != 0 && opts.BrailleDots != 6 && opts.BrailleDots != 8 {
+		return 0, 0, fmt.Errorf("braille characters must have either 6 or 8 dots, got %v", opts.BrailleDots)
+	}
+	if bounds.Empty() {
+		return 0, 0, fmt.Errorf("image has no pixels, got bounds %v", bounds)
+	}
+	fontRatio := fontRatioOf(opts)
+
+	terminalWidth, terminalHeight, detected, err := terminalSize(opts)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Dimensions are calculated from the aspect ratio first, so the image only needs to be resized once
+	if full {
+		asciiWidth = terminalWidth - 1
+
+		// To fix aspect ratio in eventual ascii art
+		asciiHeight = int(float64(keepAspectHeight(bounds, asciiWidth)) / fontRatio)
+
+	} else if (width != 0 || height != 0) && len(dimensions) == 0 {
+		// If either width or height is set and dimensions aren't given
+
 		if width != 0 && height == 0 {
 			// If width is set and height is not set, use width to calculate aspect ratio
 
 			asciiWidth = width
 
-			smallImg = imaging.Resize(img, asciiWidth, 0, imaging.Lanczos)
-			asciiHeight = smallImg.Bounds().Max.Y - smallImg.Bounds().Min.Y
-
-			asciiHeight = int(0.5 * float64(asciiHeight))
-			if asciiHeight == 0 {
-				asciiHeight = 1
-			}
+			asciiHeight = int(float64(keepAspectHeight(bounds, asciiWidth)) / fontRatio)
 
 		} else if height != 0 && width == 0 {
 			// If height is set and width is not set, use height to calculate aspect ratio
 
 			asciiHeight = height
 
-			smallImg = imaging.Resize(img, 0, asciiHeight, imaging.Lanczos)
-			asciiWidth = smallImg.Bounds().Max.X - smallImg.Bounds().Min.X
-
-			asciiWidth = int(2 * float64(asciiWidth))
-
-			if asciiWidth > terminalWidth-1 {
-				return nil, fmt.Errorf("width calculated with aspect ratio exceeds terminal width")
-			}
+			asciiWidth = int(fontRatio * float64(keepAspectWidth(bounds, asciiHeight)))
 
 		} else {
-			return nil, fmt.Errorf("both width and height can't be set. Use dimensions instead")
-		}
-
-		if isBraille {
-			asciiWidth, asciiHeight = resizeForBraille(asciiWidth, asciiHeight)
+			return 0, 0, fmt.Errorf("%w, got width %v and height %v", ErrWidthAndHeightSet, width, height)
 		}
-		smallImg = imaging.Resize(img, asciiWidth, asciiHeight, imaging.Lanczos)
 
 	} else if len(dimensions) == 0 {
 		// This condition calculates aspect ratio according to terminal height
 
 		asciiHeight = terminalHeight - 1
 
-		smallImg = imaging.Resize(img, 0, asciiHeight, imaging.Lanczos)
-		asciiWidth = smallImg.Bounds().Max.X - smallImg.Bounds().Min.X
-
 		// To fix aspect ratio in eventual ascii art
-		asciiWidth = int(2 * float64(asciiWidth))
+		asciiWidth = int(fontRatio * float64(keepAspectWidth(bounds, asciiHeight)))
 
 		// If ascii width exceeds terminal width, change ratio with respect to terminal width
 		if asciiWidth >= terminalWidth {
 			asciiWidth = terminalWidth - 1
 
-			smallImg = imaging.Resize(img, asciiWidth, 0, imaging.Lanczos)
-
-			asciiHeight = smallImg.Bounds().Max.Y - smallImg.Bounds().Min.Y
-
 			// To fix aspect ratio in eventual ascii art
-			asciiHeight = int(0.5 * float64(asciiHeight))
-		}
-
-		if isBraille {
-			asciiWidth, asciiHeight = resizeForBraille(asciiWidth, asciiHeight)
+			asciiHeight = int(float64(keepAspectHeight(bounds, asciiWidth)) / fontRatio)
 		}
-		smallImg = imaging.Resize(img, asciiWidth, asciiHeight, imaging.Lanczos)
 
 	} else {
-		asciiWidth = dimensions[0]
-		asciiHeight = dimensions[1]
-
-		if isBraille {
-			asciiWidth, asciiHeight = resizeForBraille(asciiWidth, asciiHeight)
-		}
-		smallImg = imaging.Resize(img, asciiWidth, asciiHeight, imaging.Lanczos)
+		asciiWidth, asciiHeight = fitDimensions(bounds, dimensions[0], dimensions[1], opts)
 	}
 
+	// Very wide or tall images, as well as tiny terminals, can round either side down to 0. A 0 would make
+	// imaging.Resize() keep the aspect ratio instead, which doesn't fit braille and block characters
+	asciiWidth = maxInt(asciiWidth, 1)
+	asciiHeight = maxInt(asciiHeight, 1)
+
 	// Repeated despite being in cmd/root.go to maintain support for library
 	//
-	// If there are passed dimensions, check whether the width exceeds terminal width
-	if len(dimensions) > 0 && !full {
-		if dimensions[0] > terminalWidth-1 {
-			return nil, fmt.Errorf("set width must be lower than terminal width")
+	// Every branch is checked here in characters, since that's what takes up the terminal's width. Braille art is
+	// resized to twice as many pixels as its width by resizeForSubPixels(), but each pair of them is a single character
+	if detected {
+		if err := checkTerminalWidth(asciiWidth, terminalWidth, dimensions, width, height, full); err != nil {
+			return 0, 0, err
 		}
 	}
 
-	var imgSet [][]AsciiPixel
+	asciiWidth, asciiHeight = resizeForSubPixels(asciiWidth, asciiHeight, isBraille, opts)
 
-	b := smallImg.Bounds()
+	return asciiWidth, asciiHeight, nil
+}
 
-	// These nested loops iterate through each pixel of resized image and get an AsciiPixel instance
-	for y := b.Min.Y; y < b.Max.Y; y++ {
+/*
+Returns an error wrapping ErrWidthExceedsTerminal if ascii art asciiWidth characters wide doesn't fit in the terminal.
+Set dimensions are checked instead of asciiWidth, since contained ascii art is padded to their width.
+*/
+func checkTerminalWidth(asciiWidth, terminalWidth int, dimensions []int, width, height int, full bool) error {
 
-		var temp []AsciiPixel
-		for x := b.Min.X; x < b.Max.X; x++ {
+	if len(dimensions) > 0 && !full {
+		asciiWidth = maxInt(asciiWidth, dimensions[0])
+	}
+	if asciiWidth <= terminalWidth-1 {
+		return nil
+	}
 
-			oldPixel := smallImg.At(x, y)
-			grayPixel := color.GrayModel.Convert(oldPixel)
+	switch {
+	case full:
+		return fmt.Errorf("%w: terminal width %v leaves no room for full width ascii art", ErrWidthExceedsTerminal, terminalWidth)
 
-			r1, g1, b1, _ := grayPixel.RGBA()
-			charDepth := r1 / 257 // Only Red is needed from RGB for charDepth in AsciiPixel since they have the same value for grayscale images
-			r1 = uint32(r1 / 257)
-			g1 = uint32(g1 / 257)
-			b1 = uint32(b1 / 257)
+	case len(dimensions) > 0 || width != 0:
+		return fmt.Errorf("%w: set width %v must be lower than terminal width %v", ErrWidthExceedsTerminal, asciiWidth, terminalWidth)
 
-			// Get co1ored RGB values of original pixel for rgbValue in AsciiPixel
-			r2, g2, b2, _ := oldPixel.RGBA()
-			r2 = uint32(r2 / 257)
-			g2 = uint32(g2 / 257)
-			b2 = uint32(b2 / 257)
+	case height != 0:
+		return fmt.Errorf("%w: width %v calculated from height %v with aspect ratio must be lower than terminal width %v",
+			ErrWidthExceedsTerminal, asciiWidth, height, terminalWidth)
 
-			temp = append(temp, AsciiPixel{
-				charDepth:      charDepth,
-				grayscaleValue: [3]uint32{r1, g1, b1},
-				rgbValue:       [3]uint32{r2, g2, b2},
-			})
+	default:
+		return fmt.Errorf("%w: width %v must be lower than terminal width %v", ErrWidthExceedsTerminal, asciiWidth, terminalWidth)
+	}
+}
 
-		}
-		imgSet = append(imgSet, temp)
+// Returns an error if dimensions are set without exactly 2 values that are both at least 1. Unset dimensions are valid
+func checkDimensions(dimensions []int) error {
+	if len(dimensions) == 0 {
+		return nil
 	}
+	if len(dimensions) != 2 {
+		return fmt.Errorf("%w: requires 2 dimensions, got %v", ErrInvalidDimensions, len(dimensions))
+	}
+	if dimensions[0] < 1 || dimensions[1] < 1 {
+		return fmt.Errorf("%w: width and height must be greater than 0, got %v", ErrInvalidDimensions, dimensions)
+	}
+	return nil
+}
 
-	// This rarely affects performance since the ascii art 2D slice size isn't that large
-	if flipX || flipY {
-		imgSet = reverse(imgSet, flipX, flipY)
+/*
+Returns opts.TerminalWidth and opts.TerminalHeight, querying the terminal only if either of them isn't set. When
+there's no terminal to query, such as when output is redirected in CI, FallbackTerminalWidth and
+FallbackTerminalHeight are used for the ones that aren't set and false is returned, since set widths can't
+exceed a terminal that isn't there.
+*/
+func terminalSize(opts PixelOptions) (int, int, bool, error) {
+	if opts.TerminalWidth < 0 || opts.TerminalHeight < 0 {
+		return 0, 0, false, fmt.Errorf("terminal size can't be negative")
+	}
+	if opts.TerminalWidth > 0 && opts.TerminalHeight > 0 {
+		return opts.TerminalWidth, opts.TerminalHeight, true, nil
 	}
 
-	return imgSet, nil
+	terminalWidth, terminalHeight, err := winsize.GetTerminalSize()
+	detected := err == nil && terminalWidth > 0 && terminalHeight > 0
+	if !detected {
+		terminalWidth, terminalHeight = FallbackTerminalWidth, FallbackTerminalHeight
+	}
+
+	if opts.TerminalWidth > 0 {
+		terminalWidth = opts.TerminalWidth
+	}
+	if opts.TerminalHeight > 0 {
+		terminalHeight = opts.TerminalHeight
+	}
+	return terminalWidth, terminalHeight, detected, nil
+}
+
+// Returns the height imaging.Resize() gives an image of passed bounds when it's resized to width with a height of 0
+func keepAspectHeight(bounds image.Rectangle, width int) int {
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		return 0
+	}
+	return int(math.Max(1, math.Floor(float64(width)*float64(bounds.Dy())/float64(bounds.Dx())+0.5)))
+}
+
+// Returns the width imaging.Resize() gives an image of passed bounds when it's resized to height with a width of 0
+func keepAspectWidth(bounds image.Rectangle, height int) int {
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		return 0
+	}
+	return int(math.Max(1, math.Floor(float64(height)*float64(bounds.Dx())/float64(bounds.Dy())+0.5)))
 }
 
 func reverse(imgSet [][]AsciiPixel, flipX, flipY bool) [][]AsciiPixel {
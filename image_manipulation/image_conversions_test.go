@@ -0,0 +1,306 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"runtime"
+	"testing"
+
+	"github.com/disintegration/imaging"
+)
+
+// Returns a width x height image with a diagonal gradient, so resizing has something to sample
+func gradientImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			value := uint8((x*255/width + y*255/height) / 2)
+			img.SetRGBA(x, y, color.RGBA{value, 255 - value, value / 2, 255})
+		}
+	}
+	return img
+}
+
+func BenchmarkSamplePixels(b *testing.B) {
+	img := imaging.Clone(gradientImage(3840, 2160))
+
+	// A 4K source sampled on a single goroutine, then on as many as GOMAXPROCS allows
+	for _, concurrency := range []int{1, runtime.GOMAXPROCS(0)} {
+		b.Run(fmt.Sprintf("concurrency %d", concurrency), func(b *testing.B) {
+			opts := PixelOptions{MaxConcurrency: concurrency}
+			for i := 0; i < b.N; i++ {
+				if _, err := samplePixels(context.Background(), img, opts, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestKeepAspectMatchesResize(t *testing.T) {
+	sizes := []struct {
+		name          string
+		width, height int
+	}{
+		{"square", 300, 300},
+		{"landscape 4:3", 640, 480},
+		{"landscape 16:9", 1920, 1080},
+		{"portrait 9:16", 270, 480},
+		{"odd", 333, 127},
+		{"thin", 2000, 3},
+	}
+
+	for _, size := range sizes {
+		img := gradientImage(size.width, size.height)
+		bounds := img.Bounds()
+
+		for _, target := range []int{1, 7, 79, 100, 239} {
+			want := imaging.Resize(img, target, 0, imaging.NearestNeighbor).Bounds().Dy()
+			if got := keepAspectHeight(bounds, target); got != want {
+				t.Errorf("%s: keepAspectHeight(%d) = %d, imaging.Resize() gives %d", size.name, target, got, want)
+			}
+
+			want = imaging.Resize(img, 0, target, imaging.NearestNeighbor).Bounds().Dx()
+			if got := keepAspectWidth(bounds, target); got != want {
+				t.Errorf("%s: keepAspectWidth(%d) = %d, imaging.Resize() gives %d", size.name, target, got, want)
+			}
+		}
+	}
+}
+
+// Hides the concrete type of an image, so samplePixelRow() falls back to At()
+type atOnlyImage struct {
+	image.Image
+}
+
+type namedImage struct {
+	name string
+	img  image.Image
+}
+
+// Returns images of each type samplePixelRow() reads Pix of, with varied colors and alpha
+func fastPathImages() []namedImage {
+	bounds := image.Rect(3, 5, 3+97, 5+31)
+	rgba := image.NewRGBA(bounds)
+	nrgba := image.NewNRGBA(bounds)
+	gray := image.NewGray(bounds)
+	rgba64 := image.NewRGBA64(bounds)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBA{uint8(x * 7), uint8(y * 13), uint8(x * y), uint8(x*5 + y*3)}
+			rgba.Set(x, y, c)
+			nrgba.Set(x, y, c)
+			gray.Set(x, y, c)
+
+			// Low bytes differ from high ones, so both halves of each 16-bit channel are checked
+			a := uint32(x*1021+y*317) & 0xffff
+			premultiply := func(v int) uint16 { return uint16((uint32(v) & 0xffff) * a / 0xffff) }
+			rgba64.SetRGBA64(x, y, color.RGBA64{premultiply(x * y * 263), premultiply(x * 661), premultiply(y * 1597), uint16(a)})
+		}
+	}
+
+	// A sub-image starts partway through Pix, so offsets into it are tested as well
+	return []namedImage{
+		{"RGBA", rgba},
+		{"NRGBA", nrgba},
+		{"Gray", gray},
+		{"RGBA64", rgba64},
+		{"sub RGBA", rgba.SubImage(image.Rect(10, 8, 60, 20))},
+		{"sub NRGBA", nrgba.SubImage(image.Rect(10, 8, 60, 20))},
+		{"sub Gray", gray.SubImage(image.Rect(10, 8, 60, 20))},
+		{"sub RGBA64", rgba64.SubImage(image.Rect(10, 8, 60, 20))},
+	}
+}
+
+func TestSamplePixelRowFastPath(t *testing.T) {
+	optionSets := map[string]PixelOptions{
+		"default":        {},
+		"high precision": {HighPrecision: true},
+		"background":     {Background: color.RGBA{200, 120, 40, 255}},
+		"alpha weighted": {AlphaWeighted: true},
+		"checkerboard":   {Checkerboard: true},
+	}
+
+	for _, named := range fastPathImages() {
+		name, img := named.name, named.img
+		for optsName, opts := range optionSets {
+			b := img.Bounds()
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				got := samplePixelRow(img, y, opts, nil)
+				want := samplePixelRow(atOnlyImage{img}, y, opts, nil)
+
+				for x := range want {
+					if got[x] != want[x] {
+						t.Fatalf("%s, %s: pixel %d of row %d is %+v, At() gives %+v", name, optsName, x, y, got[x], want[x])
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkSamplePixelRow(b *testing.B) {
+	for _, named := range fastPathImages() {
+		for _, path := range []namedImage{
+			{"Pix", named.img},
+			{"At", atOnlyImage{named.img}},
+		} {
+			b.Run(named.name+"/"+path.name, func(b *testing.B) {
+				bounds := path.img.Bounds()
+				row := make([]AsciiPixel, bounds.Dx())
+				for i := 0; i < b.N; i++ {
+					for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+						row = samplePixelRow(path.img, y, PixelOptions{}, row)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkPreallocation(b *testing.B) {
+	img := imaging.Clone(gradientImage(200, 60))
+	opts := PixelOptions{MaxConcurrency: 1}
+
+	b.Run("samplePixels new rows", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := samplePixels(context.Background(), img, opts, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("samplePixels reused rows", func(b *testing.B) {
+		buf, err := samplePixels(context.Background(), img, opts, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if buf, err = samplePixels(context.Background(), img, opts, buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	imgSet, err := samplePixels(context.Background(), img, opts, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("ConvertToAsciiChars", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ConvertToAsciiChars(imgSet, false, false, false, false, "", [3]int{255, 255, 255})
+		}
+	})
+
+	b.Run("ConvertToAsciiChars colored", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ConvertToAsciiChars(imgSet, false, true, false, false, "", [3]int{255, 255, 255})
+		}
+	})
+}
+
+// Resizes img the way full mode did before sizes were calculated from the aspect ratio, which resized it twice
+func doubleResizeFull(img image.Image, isBraille bool, opts PixelOptions) (image.Image, error) {
+	filter, err := ResizeFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	asciiWidth := opts.TerminalWidth - 1
+
+	smallImg := imaging.Resize(img, asciiWidth, 0, filter)
+	asciiHeight := smallImg.Bounds().Max.Y - smallImg.Bounds().Min.Y
+
+	// Default font ratio
+	asciiHeight = int(float64(asciiHeight) / 2)
+
+	asciiWidth, asciiHeight = resizeForSubPixels(asciiWidth, asciiHeight, isBraille, opts)
+	return imaging.Resize(img, asciiWidth, asciiHeight, filter), nil
+}
+
+func TestFullResizeMatchesDoubleResize(t *testing.T) {
+	sizes := []struct {
+		name          string
+		width, height int
+	}{
+		{"square", 300, 300},
+		{"landscape 4:3", 640, 480},
+		{"landscape 16:9", 1920, 1080},
+		{"portrait 9:16", 270, 480},
+		{"odd", 333, 127},
+	}
+
+	for _, size := range sizes {
+		img := gradientImage(size.width, size.height)
+
+		for _, isBraille := range []bool{false, true} {
+			opts := PixelOptions{TerminalWidth: 100, TerminalHeight: 30}
+
+			want, err := doubleResizeFull(img, isBraille, opts)
+			if err != nil {
+				t.Fatalf("%s: double resize returned error: %v", size.name, err)
+			}
+			got, err := ResizeImage(img, nil, 0, 0, true, isBraille, opts)
+			if err != nil {
+				t.Fatalf("%s: ResizeImage() returned error: %v", size.name, err)
+			}
+
+			if got.Bounds().Size() != want.Bounds().Size() {
+				t.Errorf("%s, braille %v: got size %v, want %v", size.name, isBraille, got.Bounds().Size(), want.Bounds().Size())
+				continue
+			}
+
+			gotImg, wantImg := imaging.Clone(got), imaging.Clone(want)
+			for y := 0; y < gotImg.Bounds().Dy(); y++ {
+				gotRow := gotImg.Pix[y*gotImg.Stride : y*gotImg.Stride+gotImg.Bounds().Dx()*4]
+				wantRow := wantImg.Pix[y*wantImg.Stride : y*wantImg.Stride+wantImg.Bounds().Dx()*4]
+				if string(gotRow) != string(wantRow) {
+					t.Errorf("%s, braille %v: row %d differs from the double resize", size.name, isBraille, y)
+					break
+				}
+			}
+
+			// Ascii art has as many characters as the double resize had pixels, or a quarter of them per braille character
+			options := []Option{WithPixelOptions(opts), WithFull()}
+			if isBraille {
+				options = append(options, WithBraille(128))
+			}
+			asciiSet, err := Convert(img, options...)
+			if err != nil {
+				t.Fatalf("%s: Convert() returned error: %v", size.name, err)
+			}
+			wantWidth, wantHeight := want.Bounds().Dx(), want.Bounds().Dy()
+			if isBraille {
+				wantWidth, wantHeight = wantWidth/2, wantHeight/4
+			}
+			if len(asciiSet) != wantHeight || len(asciiSet[0]) != wantWidth {
+				t.Errorf("%s, braille %v: got %dx%d characters, want %dx%d", size.name, isBraille, len(asciiSet[0]), len(asciiSet), wantWidth, wantHeight)
+			}
+		}
+	}
+}
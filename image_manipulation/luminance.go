@@ -0,0 +1,73 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import "fmt"
+
+// Red, green and blue weights of the luminance formulas selectable through PixelOptions.Luminance
+var luminanceWeights = map[string][3]float64{
+	"rec601":  {0.299, 0.587, 0.114},
+	"rec709":  {0.2126, 0.7152, 0.0722},
+	"average": {1.0 / 3, 1.0 / 3, 1.0 / 3},
+}
+
+// Returns an error for luminance options that can't be applied
+func checkLuminanceOptions(opts PixelOptions) error {
+	if opts.LuminanceWeights != [3]float64{} {
+		w := opts.LuminanceWeights
+		if w[0] < 0 || w[1] < 0 || w[2] < 0 {
+			return fmt.Errorf("luminance weights can't be negative")
+		}
+		return nil
+	}
+
+	if _, ok := luminanceWeights[opts.Luminance]; !ok && opts.Luminance != "" {
+		return fmt.Errorf("unknown luminance formula %q", opts.Luminance)
+	}
+	return nil
+}
+
+/*
+Returns the 16-bit grayscale value of an opaque pixel with passed 16-bit channels, weighted by opts.LuminanceWeights if
+set or the formula set in opts.Luminance otherwise. When neither is set, the result is the same as color.GrayModel as
+before, which is close to rec601, or color.Gray16Model if opts.HighPrecision is set. Their formula is computed inline
+so no color.Color has to be allocated per pixel.
+*/
+func luminance(r, g, b uint32, opts PixelOptions) uint32 {
+
+	weights, ok := luminanceWeights[opts.Luminance]
+	if opts.LuminanceWeights != [3]float64{} {
+		weights, ok = normalizeWeights(opts.LuminanceWeights), true
+	}
+	if !ok {
+		// Same coefficients as color.GrayModel, which sum to 65536
+		y := 19595*r + 38470*g + 7471*b + 1<<15
+		if opts.HighPrecision {
+			return y >> 16
+		}
+		return (y >> 24) * 0x101
+	}
+
+	value := weights[0]*float64(r) + weights[1]*float64(g) + weights[2]*float64(b)
+	return uint32(clampFloat(value+0.5, 0, 0xffff))
+}
+
+// Scales passed weights so they sum to 1. Weights should be checked with checkLuminanceOptions() beforehand
+func normalizeWeights(weights [3]float64) [3]float64 {
+	sum := weights[0] + weights[1] + weights[2]
+	return [3]float64{weights[0] / sum, weights[1] / sum, weights[2] / sum}
+}
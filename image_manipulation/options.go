@@ -0,0 +1,317 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"context"
+	"image"
+	"image/color"
+)
+
+// Configures Convert(). Options are applied in the order they're passed, so later ones override earlier ones
+type Option func(*Options)
+
+/*
+Settings of ConvertWithOptions(), holding the parameters of ConvertToAsciiPixelsWithOptions() and the character
+conversions. Zero values mean defaults, so an empty Options fits the image to the terminal and converts it to
+uncolored characters of the simple character set. Since it's a plain struct, presets can be stored and passed
+around as values, and fields can be added without breaking callers.
+*/
+type Options struct {
+	// Width and height of ascii art in characters, overriding Width, Height and Full
+	Dimensions []int
+
+	// Width or height of ascii art in characters, calculating the other one from the aspect ratio.
+	// Only one of them can be set
+	Width, Height int
+
+	// Use the full terminal width for ascii art, calculating its height from the aspect ratio
+	Full bool
+
+	// Flip ascii art horizontally or vertically
+	FlipX, FlipY bool
+
+	// Use braille characters instead of ascii characters
+	Braille bool
+
+	// Use spaces with their pixels as background colors instead of ascii characters. Overridden by other
+	// character sets in Pixel, but overrides Pixel.EdgeMode
+	BgBlock bool
+
+	// Grayscale value, from 1 to 255, pixels need to reach to fill braille dots or quadrants.
+	// Defaults to 128 when set to 0
+	Threshold int
+
+	// Use the detailed character set of 70 characters instead of the simple one of 10
+	Complex bool
+
+	// Characters mapped against, ordered from darkest to lightest, instead of the default character sets
+	Map string
+
+	// Color characters with the colors of their pixels, applied to their background if ColorBg is set
+	Colored, ColorBg bool
+
+	// Invert colors of ascii art along with its characters
+	Negative bool
+
+	// RGB values of uncolored characters. Defaults to white when nil
+	FontColor *[3]int
+
+	// Pixel-level settings, such as adjustments, dithering, color effects and half block or quadrant characters
+	Pixel PixelOptions
+
+	// Character-level settings, such as the color mode and transparency
+	Char CharOptions
+
+	// Only set through WithContext()
+	ctx context.Context
+}
+
+/*
+Converts img to ascii art, configured by passed options. This is the simplest way to use this package, since
+ConvertToAsciiPixels() and the character conversions take every setting as a separate parameter. Without
+options, img is fitted to the terminal and converted to uncolored characters of the simple character set.
+
+	asciiArt, err := image_conversions.Convert(img, image_conversions.WithWidth(80), image_conversions.WithColor())
+*/
+func Convert(img image.Image, options ...Option) ([][]AsciiChar, error) {
+	var opts Options
+	for _, option := range options {
+		option(&opts)
+	}
+	return ConvertWithOptions(img, opts)
+}
+
+// Same as Convert(), except that it's configured by an Options struct instead of separate options
+func ConvertWithOptions(img image.Image, opts Options) ([][]AsciiChar, error) {
+	asciiSet, _, err := convertWithOptions(img, opts, nil)
+	return asciiSet, err
+}
+
+// Same as ConvertWithOptions(), except that pixels are sampled into buf if it has the right size. The pixels are returned as well so they can be reused
+func convertWithOptions(img image.Image, opts Options, buf [][]AsciiPixel) ([][]AsciiChar, [][]AsciiPixel, error) {
+
+	ctx := opts.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	threshold := opts.Threshold
+	if threshold == 0 {
+		threshold = 128
+	}
+
+	fontColor := [3]int{255, 255, 255}
+	if opts.FontColor != nil {
+		fontColor = *opts.FontColor
+	}
+
+	pixel := opts.Pixel
+	if pixel.DitherLevels == 0 {
+		if opts.Braille || pixel.Quadrant || pixel.Sextant || pixel.Octant {
+			pixel.DitherLevels = 2
+		} else {
+			pixel.DitherLevels = CharacterCount(opts.Complex, opts.Map)
+		}
+	}
+
+	imgSet, _, err := convertToAsciiPixels(ctx, img, opts.Dimensions, opts.Width, opts.Height, opts.FlipX, opts.FlipY, opts.Full, opts.Braille, pixel, buf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Braille characters have to be read with the same number of dots the image was resized for
+	if opts.Char.BrailleDots == 0 {
+		opts.Char.BrailleDots = pixel.BrailleDots
+	}
+
+	switch {
+	case opts.Braille:
+		return ConvertToBrailleCharsWithOptions(imgSet, opts.Negative, opts.Colored, opts.ColorBg, fontColor, threshold, opts.Char), imgSet, nil
+	case pixel.Octant:
+		return ConvertToOctantChars(imgSet, opts.Negative, opts.Colored, opts.ColorBg, fontColor, threshold, opts.Char), imgSet, nil
+	case pixel.Sextant:
+		return ConvertToSextantChars(imgSet, opts.Negative, opts.Colored, opts.ColorBg, fontColor, threshold, opts.Char), imgSet, nil
+	case pixel.Quadrant:
+		return ConvertToQuadrantChars(imgSet, opts.Negative, opts.Colored, opts.ColorBg, fontColor, threshold, opts.Char), imgSet, nil
+	case pixel.HalfBlock:
+		return ConvertToHalfBlockChars(imgSet, opts.Negative, opts.Colored, opts.Char), imgSet, nil
+	case opts.BgBlock:
+		return ConvertToBgBlockChars(imgSet, opts.Negative, opts.Colored, opts.Char), imgSet, nil
+	case pixel.EdgeMode != "":
+		return ConvertToEdgeChars(imgSet, opts.Negative, opts.Colored, opts.ColorBg, fontColor, opts.Char), imgSet, nil
+	default:
+		return ConvertToAsciiCharsWithOptions(imgSet, opts.Negative, opts.Colored, opts.Complex, opts.ColorBg, opts.Map, fontColor, opts.Char), imgSet, nil
+	}
+}
+
+// Checks ctx between the steps of conversion, returning ctx.Err() early if it's cancelled
+func WithContext(ctx context.Context) Option {
+	return func(o *Options) { o.ctx = ctx }
+}
+
+// Sets width and height of ascii art in characters, overriding WithWidth(), WithHeight() and WithFull()
+func WithDimensions(width, height int) Option {
+	return func(o *Options) { o.Dimensions = []int{width, height} }
+}
+
+// Sets width of ascii art in characters, calculating its height from the aspect ratio
+func WithWidth(width int) Option {
+	return func(o *Options) { o.Width = width }
+}
+
+// Sets height of ascii art in characters, calculating its width from the aspect ratio
+func WithHeight(height int) Option {
+	return func(o *Options) { o.Height = height }
+}
+
+// Uses the full terminal width for ascii art, calculating its height from the aspect ratio
+func WithFull() Option {
+	return func(o *Options) { o.Full = true }
+}
+
+// Flips ascii art horizontally, vertically or both
+func WithFlip(flipX, flipY bool) Option {
+	return func(o *Options) { o.FlipX, o.FlipY = flipX, flipY }
+}
+
+// Uses braille characters, where pixels with a grayscale value of at least threshold, from 1 to 255, are filled dots
+func WithBraille(threshold int) Option {
+	return func(o *Options) { o.Braille, o.Threshold = true, threshold }
+}
+
+// Sets dots per braille character, either 8 for 2x4 dots or 6 for 2x3 dots. Only used with WithBraille()
+func WithBrailleDots(dots int) Option {
+	return func(o *Options) { o.Pixel.BrailleDots, o.Char.BrailleDots = dots, dots }
+}
+
+// Uses octant characters, where pixels with a grayscale value of at least threshold are filled octants
+func WithOctant(threshold int) Option {
+	return func(o *Options) { o.Pixel.Octant, o.Threshold = true, threshold }
+}
+
+// Uses sextant characters, where pixels with a grayscale value of at least threshold are filled sextants
+func WithSextant(threshold int) Option {
+	return func(o *Options) { o.Pixel.Sextant, o.Threshold = true, threshold }
+}
+
+// Uses quadrant block characters, where pixels with a grayscale value of at least threshold are filled quadrants
+func WithQuadrant(threshold int) Option {
+	return func(o *Options) { o.Pixel.Quadrant, o.Threshold = true, threshold }
+}
+
+// Uses half block characters colored with both foreground and background colors
+func WithHalfBlock() Option {
+	return func(o *Options) { o.Pixel.HalfBlock = true }
+}
+
+// Takes characters of semi-transparent pixels from their grayscale values weighted by alpha, giving soft edges
+func WithAlphaWeighted() Option {
+	return func(o *Options) { o.Pixel.AlphaWeighted = true }
+}
+
+// Blends transparent pixels over a checkerboard of squares size pixels wide, alternating between passed colors
+func WithCheckerboard(size int, dark, light color.Color) Option {
+	return func(o *Options) {
+		o.Pixel.Checkerboard, o.Pixel.CheckerSize, o.Pixel.CheckerColors = true, size, [2]color.Color{dark, light}
+	}
+}
+
+// Reduces colors to at most passed number, with a palette generated by method, either "median-cut" or "k-means"
+func WithQuantize(colors int, method string) Option {
+	return func(o *Options) { o.Pixel.Quantize, o.Pixel.QuantizeMethod = colors, method }
+}
+
+// Maps colors to the nearest ones of the palette registered under name, such as "cga", "gameboy", "c64" or "nes"
+func WithPalette(name string) Option {
+	return func(o *Options) { o.Pixel.Palette = name }
+}
+
+// Uses spaces colored with background colors, which draws the image as solid blocks
+func WithBgBlock() Option {
+	return func(o *Options) { o.BgBlock = true }
+}
+
+// Uses the detailed character set of 70 characters instead of the simple one of 10
+func WithComplex() Option {
+	return func(o *Options) { o.Complex = true }
+}
+
+// Uses the characters of customMap, ordered from darkest to lightest, instead of the default character sets
+func WithMap(customMap string) Option {
+	return func(o *Options) { o.Map = customMap }
+}
+
+// Colors characters with the colors of their pixels
+func WithColor() Option {
+	return func(o *Options) { o.Colored = true }
+}
+
+// Applies colors to the background of characters instead of their foreground
+func WithColorBg() Option {
+	return func(o *Options) { o.ColorBg = true }
+}
+
+// Sets escape codes used for colors. Either ColorModeTrueColor, ColorMode256, ColorMode16, ColorMode8, ColorModePlain or ColorModeAuto
+func WithColorMode(colorMode string) Option {
+	return func(o *Options) { o.Char.ColorMode = colorMode }
+}
+
+// Sets matcher that chooses the character of each pixel instead of the built-in character ramps. Unused for braille, block and edge characters
+func WithMatcher(matcher CharMatcher) Option {
+	return func(o *Options) { o.Char.Matcher = matcher }
+}
+
+// Colors both the background and the character of each pixel, darkening and lightening its color respectively
+func WithDualColor() Option {
+	return func(o *Options) { o.Char.DualColor = true }
+}
+
+// Sets metric for matching colors against the palettes of limited color modes. Either ColorDistanceCIEDE2000, ColorDistanceCIE76 or ColorDistanceRedmean
+func WithColorDistance(metric string) Option {
+	return func(o *Options) { o.Char.ColorDistance = metric }
+}
+
+// Sets color of uncolored characters from RGB values
+func WithFontColor(rgb [3]int) Option {
+	return func(o *Options) { o.FontColor = &rgb }
+}
+
+// Inverts colors of ascii art along with its characters
+func WithNegative() Option {
+	return func(o *Options) { o.Negative = true }
+}
+
+// Dithers grayscale values with passed strength from 0.0 to 1.0, using the mode of PixelOptions.DitherMode
+func WithDithering(strength float64, mode string) Option {
+	return func(o *Options) { o.Pixel.Dithering, o.Pixel.DitherMode = strength, mode }
+}
+
+// Draws edges with line characters instead of mapping brightness. Either "sobel" or "canny"
+func WithEdges(mode string) Option {
+	return func(o *Options) { o.Pixel.EdgeMode = mode }
+}
+
+// Replaces all pixel-level settings with opts, for the ones that have no Option of their own
+func WithPixelOptions(opts PixelOptions) Option {
+	return func(o *Options) { o.Pixel = opts }
+}
+
+// Replaces all character-level settings with opts, for the ones that have no Option of their own
+func WithCharOptions(opts CharOptions) Option {
+	return func(o *Options) { o.Char = opts }
+}
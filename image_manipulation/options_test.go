@@ -0,0 +1,169 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"fmt"
+	"image"
+	"testing"
+)
+
+// Terminal size passed to every test, so results don't depend on the terminal they're run in
+var testTerminal = PixelOptions{TerminalWidth: 80, TerminalHeight: 24}
+
+// Returns an error unless asciiSet has at least one row, every row has the same number of characters and that number is at least 1
+func checkAsciiGrid(asciiSet [][]AsciiChar) error {
+	if len(asciiSet) == 0 {
+		return fmt.Errorf("ascii art has no rows")
+	}
+	for y, row := range asciiSet {
+		if len(row) == 0 {
+			return fmt.Errorf("row %d has no characters", y)
+		}
+		if len(row) != len(asciiSet[0]) {
+			return fmt.Errorf("row %d has %d characters, row 0 has %d", y, len(row), len(asciiSet[0]))
+		}
+	}
+	return nil
+}
+
+func TestConvertWithOptionsExtremeSizes(t *testing.T) {
+	sizes := []struct {
+		name          string
+		width, height int
+	}{
+		{"1x1", 1, 1},
+		{"1x2", 1, 2},
+		{"2x1", 2, 1},
+		{"1xN", 1, 500},
+		{"Nx1", 500, 1},
+		{"very wide", 8000, 2},
+		{"very tall", 2, 8000},
+	}
+
+	// Full and width modes would make tall sources thousands of rows high, and height mode would make wide ones
+	// wider than the terminal, so those are skipped for such sources
+	modes := []struct {
+		name               string
+		opts               Options
+		skipTall, skipWide bool
+	}{
+		{"terminal", Options{}, false, false},
+		{"full", Options{Full: true}, true, false},
+		{"width", Options{Width: 30}, true, false},
+		{"height", Options{Height: 10}, false, true},
+		{"dimensions", Options{Dimensions: []int{20, 10}}, false, false},
+	}
+
+	characterSets := []struct {
+		name string
+		set  func(*Options)
+	}{
+		{"ascii", func(*Options) {}},
+		{"braille", func(o *Options) { o.Braille = true }},
+		{"braille 6 dots", func(o *Options) { o.Braille, o.Pixel.BrailleDots = true, 6 }},
+		{"quadrant", func(o *Options) { o.Pixel.Quadrant = true }},
+		{"half block", func(o *Options) { o.Pixel.HalfBlock = true }},
+	}
+
+	for _, size := range sizes {
+		img := gradientImage(size.width, size.height)
+		tall := size.height > size.width*4
+		wide := size.width > size.height*4
+
+		for _, mode := range modes {
+			if (tall && mode.skipTall) || (wide && mode.skipWide) {
+				continue
+			}
+			for _, characterSet := range characterSets {
+				t.Run(size.name+"/"+mode.name+"/"+characterSet.name, func(t *testing.T) {
+					opts := mode.opts
+					opts.Pixel = testTerminal
+					characterSet.set(&opts)
+
+					asciiSet, err := ConvertWithOptions(img, opts)
+					if err != nil {
+						t.Fatalf("ConvertWithOptions() returned error: %v", err)
+					}
+					if err := checkAsciiGrid(asciiSet); err != nil {
+						t.Fatal(err)
+					}
+				})
+			}
+		}
+	}
+}
+
+// Every size resizeDimensions() returns must be at least 1 pixel in each direction, however extreme the source's aspect ratio is
+func TestResizeDimensionsAtLeastOne(t *testing.T) {
+	bounds := []image.Rectangle{
+		image.Rect(0, 0, 1, 1),
+		image.Rect(0, 0, 1, 100000),
+		image.Rect(0, 0, 100000, 1),
+		image.Rect(10, 10, 11, 5000),
+	}
+
+	for _, b := range bounds {
+		for _, isBraille := range []bool{false, true} {
+			for _, full := range []bool{false, true} {
+				width, height, err := resizeDimensions(b, nil, 0, 0, full, isBraille, testTerminal)
+				if err != nil {
+					t.Errorf("bounds %v, full %v, braille %v: returned error: %v", b, full, isBraille, err)
+					continue
+				}
+				if width < 1 || height < 1 {
+					t.Errorf("bounds %v, full %v, braille %v: got %dx%d, want at least 1x1", b, full, isBraille, width, height)
+				}
+			}
+		}
+	}
+}
+
+// A set terminal size must be used as is, so ascii art is the same however the tests are run
+func TestConvertWithTerminalSize(t *testing.T) {
+	img := gradientImage(400, 300)
+	terminal := PixelOptions{TerminalWidth: 40, TerminalHeight: 10}
+
+	for _, isBraille := range []bool{false, true} {
+		options := []Option{WithPixelOptions(terminal), WithFull()}
+		if isBraille {
+			options = append(options, WithBraille(128))
+		}
+
+		asciiSet, err := Convert(img, options...)
+		if err != nil {
+			t.Fatalf("braille %v: Convert() returned error: %v", isBraille, err)
+		}
+		if err := checkAsciiGrid(asciiSet); err != nil {
+			t.Fatalf("braille %v: %v", isBraille, err)
+		}
+
+		// Full mode leaves the last column of the terminal empty, so a 40 column terminal fits 39 characters
+		if width := len(asciiSet[0]); width != terminal.TerminalWidth-1 {
+			t.Errorf("braille %v: got width %d, want %d to fit terminal width %d", isBraille, width, terminal.TerminalWidth-1, terminal.TerminalWidth)
+		}
+	}
+
+	// Without a width, ascii art is fitted to the terminal's height instead, again leaving its last row empty
+	asciiSet, err := Convert(img, WithPixelOptions(terminal))
+	if err != nil {
+		t.Fatalf("Convert() returned error: %v", err)
+	}
+	if height := len(asciiSet); height != terminal.TerminalHeight-1 {
+		t.Errorf("got height %d, want %d to fit terminal height %d", height, terminal.TerminalHeight-1, terminal.TerminalHeight)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Built-in palettes selectable through PixelOptions.Palette, keyed by name. More can be added with RegisterPalette()
+var (
+	palettesMu sync.RWMutex
+
+	palettes = map[string][][3]int{
+		// High intensity CGA palette 1
+		"cga": {
+			{0, 0, 0}, {85, 255, 255}, {255, 85, 255}, {255, 255, 255},
+		},
+
+		// Original Game Boy screen shades, from darkest to lightest
+		"gameboy": {
+			{15, 56, 15}, {48, 98, 48}, {139, 172, 15}, {155, 188, 15},
+		},
+
+		// Commodore 64 colors as measured by Pepto
+		"c64": {
+			{0, 0, 0}, {255, 255, 255}, {104, 55, 43}, {112, 164, 178},
+			{111, 61, 134}, {88, 141, 67}, {53, 40, 121}, {184, 199, 111},
+			{111, 79, 37}, {67, 57, 0}, {154, 103, 89}, {68, 68, 68},
+			{108, 108, 108}, {154, 210, 132}, {108, 94, 181}, {149, 149, 149},
+		},
+
+		// Distinct colors of the NES 2C02 PPU, leaving out the repeated blacks and whites
+		"nes": {
+			{84, 84, 84}, {0, 30, 116}, {8, 16, 144}, {48, 0, 136}, {68, 0, 100}, {92, 0, 48}, {84, 4, 0},
+			{60, 24, 0}, {32, 42, 0}, {8, 58, 0}, {0, 64, 0}, {0, 60, 0}, {0, 50, 60}, {0, 0, 0},
+			{152, 150, 152}, {8, 76, 196}, {48, 50, 236}, {92, 30, 228}, {136, 20, 176}, {160, 20, 100}, {152, 34, 32},
+			{120, 60, 0}, {84, 90, 0}, {40, 114, 0}, {8, 124, 0}, {0, 118, 40}, {0, 102, 120},
+			{236, 238, 236}, {76, 154, 236}, {120, 124, 236}, {176, 98, 236}, {228, 84, 236}, {236, 88, 180}, {236, 106, 100},
+			{212, 136, 32}, {160, 170, 0}, {116, 196, 0}, {76, 208, 32}, {56, 204, 108}, {56, 180, 204}, {60, 60, 60},
+			{168, 204, 236}, {188, 188, 236}, {212, 178, 236}, {236, 174, 236}, {236, 174, 212}, {236, 180, 176},
+			{228, 196, 144}, {204, 210, 120}, {180, 222, 120}, {168, 226, 144}, {152, 226, 180}, {160, 214, 228}, {160, 162, 160},
+		},
+	}
+)
+
+/*
+Adds a palette that PixelOptions.Palette can select by name, replacing any palette of the same name, including
+built-in ones. Each color holds red, green and blue values from 0 to 255. It's safe to call concurrently with
+conversions, although palettes are usually registered once during initialization.
+*/
+func RegisterPalette(name string, colors [][3]int) error {
+	if name == "" {
+		return fmt.Errorf("palette name can't be empty")
+	}
+	if err := checkPaletteColors(colors); err != nil {
+		return err
+	}
+
+	palettesMu.Lock()
+	defer palettesMu.Unlock()
+
+	palettes[name] = append([][3]int(nil), colors...)
+	return nil
+}
+
+// Returns the names of all palettes that PixelOptions.Palette accepts, in alphabetical order
+func PaletteNames() []string {
+	palettesMu.RLock()
+	defer palettesMu.RUnlock()
+
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Returns an error for palette options that can't be applied
+func checkPaletteOptions(opts PixelOptions) error {
+	if opts.PaletteColors != nil {
+		return checkPaletteColors(opts.PaletteColors)
+	}
+	if opts.Palette == "" {
+		return nil
+	}
+
+	palettesMu.RLock()
+	defer palettesMu.RUnlock()
+
+	if _, ok := palettes[opts.Palette]; !ok {
+		return fmt.Errorf("unknown palette %q", opts.Palette)
+	}
+	return nil
+}
+
+func checkPaletteColors(colors [][3]int) error {
+	if len(colors) == 0 {
+		return fmt.Errorf("palette needs at least one color")
+	}
+	for _, rgb := range colors {
+		for _, value := range rgb {
+			if value < 0 || value > 255 {
+				return fmt.Errorf("palette color values must be between 0 and 255")
+			}
+		}
+	}
+	return nil
+}
+
+// Returns opts.PaletteColors, or the palette named by opts.Palette if it isn't set. Returns nil if neither is set
+func selectedPalette(opts PixelOptions) [][3]int {
+	if opts.PaletteColors != nil {
+		return opts.PaletteColors
+	}
+
+	palettesMu.RLock()
+	defer palettesMu.RUnlock()
+
+	return palettes[opts.Palette]
+}
+
+// Replaces the RGB values of each AsciiPixel in imgSet with the nearest color of palette, measured with passed metric.
+// Grayscale values are left untouched
+func paletteImgSet(imgSet [][]AsciiPixel, palette [][3]int, metric string) {
+
+	labPalette := make([][3]float64, len(palette))
+	for i, rgb := range palette {
+		labPalette[i] = rgbToLab(rgb)
+	}
+
+	// Images usually repeat colors a lot, so each one's nearest palette color is only searched once
+	nearest := make(map[[3]uint32][3]uint32)
+
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			rgb := imgSet[y][x].rgbValue
+
+			match, ok := nearest[rgb]
+			if !ok {
+				c := palette[nearestColor(toIntRgb(rgb), palette, labPalette, metric)]
+				match = [3]uint32{uint32(c[0]), uint32(c[1]), uint32(c[2])}
+				nearest[rgb] = match
+			}
+			imgSet[y][x].rgbValue = match
+		}
+	}
+}
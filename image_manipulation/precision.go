@@ -0,0 +1,130 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"image"
+	"math"
+	"sync"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/draw"
+)
+
+// 16-bit lookup tables between sRGB and linear light values, built the first time PixelOptions.LinearResize is used
+var (
+	linearTablesOnce sync.Once
+	srgbToLinear     []uint16
+	linearToSrgb     []uint16
+)
+
+/*
+Resizes img to passed dimensions with filter. imaging.Resize() always returns 8-bit pixels, so if opts.HighPrecision
+or opts.LinearResize is set, the image is scaled with the same filter through golang.org/x/image/draw into a 16-bit
+image instead. With opts.LinearResize, pixels are converted to linear light before scaling and back to sRGB after
+it, so averaged pixels keep their actual brightness.
+*/
+func resizeWithFilter(img image.Image, width, height int, filter imaging.ResampleFilter, opts PixelOptions) image.Image {
+
+	if !opts.HighPrecision && !opts.LinearResize {
+		return imaging.Resize(img, width, height, filter)
+	}
+
+	var scaler draw.Scaler = draw.NearestNeighbor
+	if filter.Kernel != nil {
+		scaler = &draw.Kernel{Support: filter.Support, At: filter.Kernel}
+	}
+
+	if opts.LinearResize {
+		linearTablesOnce.Do(buildLinearTables)
+		img = convertTransfer(img, srgbToLinear)
+	}
+
+	dst := image.NewRGBA64(image.Rect(0, 0, width, height))
+	scaler.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	if opts.LinearResize {
+		return convertTransfer(dst, linearToSrgb)
+	}
+	return dst
+}
+
+func buildLinearTables() {
+	srgbToLinear = make([]uint16, 0x10000)
+	linearToSrgb = make([]uint16, 0x10000)
+
+	for i := range srgbToLinear {
+		value := float64(i) / 0xffff
+
+		var linear, srgb float64
+		if value <= 0.04045 {
+			linear = value / 12.92
+		} else {
+			linear = math.Pow((value+0.055)/1.055, 2.4)
+		}
+		if value <= 0.0031308 {
+			srgb = value * 12.92
+		} else {
+			srgb = 1.055*math.Pow(value, 1/2.4) - 0.055
+		}
+
+		srgbToLinear[i] = uint16(math.Round(linear * 0xffff))
+		linearToSrgb[i] = uint16(math.Round(srgb * 0xffff))
+	}
+}
+
+/*
+Returns a 16-bit copy of img with each color channel mapped through table. Channels are un-premultiplied first,
+since the transfer functions apply to colors and not to alpha, and premultiplied again afterwards.
+*/
+func convertTransfer(img image.Image, table []uint16) *image.RGBA64 {
+
+	b := img.Bounds()
+	dst := image.NewRGBA64(image.Rect(0, 0, b.Dx(), b.Dy()))
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		i := dst.PixOffset(0, y-b.Min.Y)
+
+		for x := b.Min.X; x < b.Max.X; x, i = x+1, i+8 {
+			r, g, bl, a := img.At(x, y).RGBA()
+
+			if a != 0 {
+				for c, value := range [3]uint32{r, g, bl} {
+					value = uint32(table[value*0xffff/a]) * a / 0xffff
+					dst.Pix[i+2*c] = uint8(value >> 8)
+					dst.Pix[i+2*c+1] = uint8(value)
+				}
+			}
+			dst.Pix[i+6] = uint8(a >> 8)
+			dst.Pix[i+7] = uint8(a)
+		}
+	}
+
+	return dst
+}
+
+/*
+Returns the value of pixel that characters are chosen by, along with the maximum that value can have. This is the
+16-bit depth kept by PixelOptions.HighPrecision, unless a later step such as adjustments, equalization or
+dithering changed charDepth without it, in which case the 8-bit charDepth is returned.
+*/
+func depthValue(pixel AsciiPixel) (float64, float64) {
+	if pixel.hasDepth16 {
+		return float64(pixel.depth16), 0xffff
+	}
+	return float64(pixel.charDepth), MAX_VAL
+}
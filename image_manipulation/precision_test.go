@@ -0,0 +1,76 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"testing"
+)
+
+func TestDepthValueHighPrecision(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         uint32
+		opts          PixelOptions
+		want, wantMax float64
+	}{
+		{"black", 0, PixelOptions{HighPrecision: true}, 0, 0xffff},
+		{"gray", 0x8081, PixelOptions{HighPrecision: true}, 0x8081, 0xffff},
+		{"white", 0xffff, PixelOptions{HighPrecision: true}, 0xffff, 0xffff},
+		{"gray without high precision", 0x8081, PixelOptions{}, 0x80, MAX_VAL},
+	}
+
+	for _, test := range tests {
+		pixel := newAsciiPixel(test.value, test.value, test.value, 0xffff, nil, test.opts)
+		if got, gotMax := depthValue(pixel); got != test.want || gotMax != test.wantMax {
+			t.Errorf("%s: got %v out of %v, want %v out of %v", test.name, got, gotMax, test.want, test.wantMax)
+		}
+
+		// Inverting keeps the 16-bit depth, inverted as well
+		imgSet := [][]AsciiPixel{{pixel}}
+		invertImgSet(imgSet)
+		if got, _ := depthValue(imgSet[0][0]); got != test.wantMax-test.want {
+			t.Errorf("%s inverted: got %v, want %v", test.name, got, test.wantMax-test.want)
+		}
+	}
+}
+
+// Dithering only changes the 8-bit charDepth, so characters must be chosen by it rather than the 16-bit depth
+func TestDepthValueHighPrecisionDithered(t *testing.T) {
+	img := gradientImage(160, 90)
+
+	for _, mode := range []string{"bayer", "floyd-steinberg"} {
+		opts := testTerminal
+		opts.HighPrecision = true
+		opts.Dithering = 1
+		opts.DitherMode = mode
+		opts.DitherLevels = 4
+
+		imgSet, _, err := ConvertToAsciiPixelsWithOptions(img, nil, 0, 0, false, false, false, false, opts)
+		if err != nil {
+			t.Fatalf("%s: ConvertToAsciiPixelsWithOptions() returned error: %v", mode, err)
+		}
+
+		for y, row := range imgSet {
+			for x, pixel := range row {
+				if got, gotMax := depthValue(pixel); got != float64(pixel.charDepth) || gotMax != MAX_VAL {
+					t.Fatalf("%s: pixel %d of row %d gives %v out of %v, want its dithered charDepth %d out of %v",
+						mode, x, y, got, gotMax, pixel.charDepth, MAX_VAL)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Rounds of k-means refinement run on the median cut palette for the "k-means" quantize method
+const kMeansIterations = 8
+
+// Returns an error for quantization options that can't be applied
+func checkQuantizeOptions(opts PixelOptions) error {
+	if opts.Quantize != 0 && (opts.Quantize < 2 || opts.Quantize > 256) {
+		return fmt.Errorf("quantize colors must be between 2 and 256")
+	}
+
+	switch opts.QuantizeMethod {
+	case "", "median-cut", "k-means":
+		return nil
+	default:
+		return fmt.Errorf("unknown quantize method %q", opts.QuantizeMethod)
+	}
+}
+
+/*
+Reduces the RGB values of imgSet to at most passed number of colors. The palette is generated from the pixels
+themselves by median cut, and refined with a few rounds of k-means clustering if method is "k-means". Each pixel
+is then replaced with its nearest palette color. Grayscale values are left untouched.
+*/
+func quantizeImgSet(imgSet [][]AsciiPixel, colors int, method string) {
+
+	var pixels [][3]uint32
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			pixels = append(pixels, imgSet[y][x].rgbValue)
+		}
+	}
+	if len(pixels) == 0 {
+		return
+	}
+
+	palette := medianCut(append([][3]uint32(nil), pixels...), colors)
+	if method == "k-means" {
+		palette = kMeans(pixels, palette, kMeansIterations)
+	}
+
+	// Images usually repeat colors a lot, so each one's nearest palette color is only searched once
+	nearest := make(map[[3]uint32][3]uint32)
+
+	for y := range imgSet {
+		for x := range imgSet[y] {
+			rgb := imgSet[y][x].rgbValue
+
+			match, ok := nearest[rgb]
+			if !ok {
+				match = palette[nearestColorIndex(rgb, palette)]
+				nearest[rgb] = match
+			}
+			imgSet[y][x].rgbValue = match
+		}
+	}
+}
+
+/*
+Splits pixels into at most count boxes by repeatedly cutting the box with the widest range in any channel at its
+median along that channel, and returns the average color of each box. Fewer colors are returned if pixels don't
+have enough distinct ones. The order of pixels is changed.
+*/
+func medianCut(pixels [][3]uint32, count int) [][3]uint32 {
+
+	boxes := [][][3]uint32{pixels}
+
+	for len(boxes) < count {
+		best, bestChannel, bestRange := -1, 0, uint32(0)
+		for i, box := range boxes {
+			channel, channelRange := widestChannel(box)
+			if channelRange > bestRange {
+				best, bestChannel, bestRange = i, channel, channelRange
+			}
+		}
+
+		// Every box holds a single color
+		if best == -1 {
+			break
+		}
+
+		box := boxes[best]
+		sort.Slice(box, func(i, j int) bool { return box[i][bestChannel] < box[j][bestChannel] })
+
+		median := len(box) / 2
+		boxes[best] = box[:median]
+		boxes = append(boxes, box[median:])
+	}
+
+	palette := make([][3]uint32, len(boxes))
+	for i, box := range boxes {
+		palette[i] = averageRgb(box)
+	}
+
+	return palette
+}
+
+// Returns the channel with the largest difference between the minimum and maximum values of pixels, along with it
+func widestChannel(pixels [][3]uint32) (int, uint32) {
+
+	min := [3]uint32{255, 255, 255}
+	var max [3]uint32
+
+	for _, rgb := range pixels {
+		for c := 0; c < 3; c++ {
+			if rgb[c] < min[c] {
+				min[c] = rgb[c]
+			}
+			if rgb[c] > max[c] {
+				max[c] = rgb[c]
+			}
+		}
+	}
+
+	channel := 0
+	for c := 1; c < 3; c++ {
+		if max[c]-min[c] > max[channel]-min[channel] {
+			channel = c
+		}
+	}
+
+	return channel, max[channel] - min[channel]
+}
+
+// Moves each color of palette to the average of the pixels nearest to it, for passed number of iterations.
+// Colors that no pixels are nearest to are kept as they are
+func kMeans(pixels [][3]uint32, palette [][3]uint32, iterations int) [][3]uint32 {
+
+	sums := make([][3]uint64, len(palette))
+	counts := make([]uint64, len(palette))
+
+	for iteration := 0; iteration < iterations; iteration++ {
+		for i := range palette {
+			sums[i], counts[i] = [3]uint64{}, 0
+		}
+
+		for _, rgb := range pixels {
+			i := nearestColorIndex(rgb, palette)
+			for c := 0; c < 3; c++ {
+				sums[i][c] += uint64(rgb[c])
+			}
+			counts[i]++
+		}
+
+		for i := range palette {
+			if counts[i] == 0 {
+				continue
+			}
+			for c := 0; c < 3; c++ {
+				palette[i][c] = uint32((sums[i][c] + counts[i]/2) / counts[i])
+			}
+		}
+	}
+
+	return palette
+}
+
+// Returns the index of the color in palette with the smallest squared distance to rgb
+func nearestColorIndex(rgb [3]uint32, palette [][3]uint32) int {
+
+	best, bestDistance := 0, -1
+
+	for i, color := range palette {
+		distance := 0
+		for c := 0; c < 3; c++ {
+			diff := int(rgb[c]) - int(color[c])
+			distance += diff * diff
+		}
+
+		if bestDistance == -1 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+
+	return best
+}
+
+// Returns the rounded average of passed colors
+func averageRgb(pixels [][3]uint32) [3]uint32 {
+
+	var sum [3]uint64
+	for _, rgb := range pixels {
+		for c := 0; c < 3; c++ {
+			sum[c] += uint64(rgb[c])
+		}
+	}
+
+	n := uint64(len(pixels))
+	return [3]uint32{
+		uint32((sum[0] + n/2) / n),
+		uint32((sum[1] + n/2) / n),
+		uint32((sum[2] + n/2) / n),
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"context"
+	"image"
+)
+
+// Rows of ascii art per strip converted by StreamAsciiPixels() when tileRows isn't greater than 0
+const defaultStreamTileRows = 16
+
+// A single row of AsciiPixel instances sent by StreamAsciiPixels()
+type RowResult struct {
+	// Index of the row, counting from the top of the returned pixels
+	Y int
+
+	Pixels []AsciiPixel
+
+	// Set on the last result if conversion failed or ctx was cancelled, in which case Pixels is nil
+	Err error
+}
+
+/*
+Same as ConvertToAsciiPixelsContext(), except that rows of AsciiPixel instances are sent on the returned channel in
+order as they're converted, so callers can render them without waiting for the whole image. The image is converted
+in strips of tileRows rows of ascii art, or 16 if tileRows isn't greater than 0, the way ConvertToAsciiPixelTiles()
+does, and each strip's rows are sent as soon as it's done.
+
+Besides the decoded image, only the strip being sent is held in memory, since the next one isn't converted until
+every row of it has been received. Rows aren't reused, so callers may keep them. As with ConvertToAsciiPixelTiles(),
+steps that look at more than a single pixel, such as equalizing, gradients, quantizing, edge detection and dithering,
+are done on each strip separately, and resampled values can differ by a level from converting the whole image.
+
+The channel is closed once every row is sent. If conversion fails, the error is sent as the last result. Cancelling
+ctx stops the conversion, so callers that stop reading early should cancel it to free the converting goroutine.
+*/
+func StreamAsciiPixels(ctx context.Context, img image.Image, dimensions []int, width, height int, flipX, flipY, full, isBraille bool, opts PixelOptions, tileRows int) <-chan RowResult {
+
+	rows := make(chan RowResult)
+
+	go func() {
+		defer close(rows)
+
+		y := 0
+
+		emit := func(imgSet [][]AsciiPixel) error {
+			for _, pixels := range imgSet {
+				select {
+				case rows <- RowResult{Y: y, Pixels: pixels}:
+					y++
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		}
+
+		if tileRows <= 0 {
+			tileRows = defaultStreamTileRows
+		}
+
+		_, err := ConvertToAsciiPixelTiles(ctx, img, dimensions, width, height, flipX, flipY, full, isBraille, opts, tileRows, emit)
+
+		if err != nil {
+			select {
+			case rows <- RowResult{Y: y, Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return rows
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"context"
+	"testing"
+)
+
+// Without tileRows, rows must still arrive in strips and match the conversion of the whole image
+func TestStreamAsciiPixelsDefaultStrips(t *testing.T) {
+	img := gradientImage(300, 200)
+	opts := PixelOptions{TerminalWidth: 80, TerminalHeight: 60}
+
+	want, _, err := ConvertToAsciiPixelsWithOptions(img, nil, 0, 0, false, false, false, false, opts)
+	if err != nil {
+		t.Fatalf("ConvertToAsciiPixelsWithOptions() returned error: %v", err)
+	}
+	if len(want) <= defaultStreamTileRows {
+		t.Fatalf("got %d rows, need more than %d for multiple strips", len(want), defaultStreamTileRows)
+	}
+
+	y := 0
+	for row := range StreamAsciiPixels(context.Background(), img, nil, 0, 0, false, false, false, false, opts, 0) {
+		if row.Err != nil {
+			t.Fatalf("row %d: %v", row.Y, row.Err)
+		}
+		if row.Y != y {
+			t.Fatalf("got row %d, want %d", row.Y, y)
+		}
+		if len(row.Pixels) != len(want[y]) {
+			t.Fatalf("row %d has %d pixels, want %d", y, len(row.Pixels), len(want[y]))
+		}
+		// Each strip is resampled from its own band of the image, which can round differently by a level
+		for x := range row.Pixels {
+			got, wantPixel := row.Pixels[x], want[y][x]
+			for c := 0; c < 3; c++ {
+				if absDiff(got.rgbValue[c], wantPixel.rgbValue[c]) > 2 {
+					t.Fatalf("pixel %d of row %d is %v, want %v", x, y, got.rgbValue, wantPixel.rgbValue)
+				}
+			}
+		}
+		y++
+	}
+
+	if y != len(want) {
+		t.Errorf("got %d rows, want %d", y, len(want))
+	}
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+/*
+Same as ConvertToAsciiPixelsContext(), except that the image is resized and converted in horizontal strips of tileRows
+rows of ascii art, which are passed to emit one at a time from the top of the eventual ascii art to its bottom. Only
+a single strip is held in memory at once, which bounds memory usage for very large images such as panoramas, although
+the decoded image itself still needs to fit in memory.
+
+Each strip is resized from its own band of the source image, extended by a few rows above and below so the resampling
+filter has the same neighboring pixels at seams as it would for the whole image. These extra rows are dropped before
+emit is called. Since dithering and edge detection run on each strip separately, they don't carry across seams.
+*/
+func ConvertToAsciiPixelTiles(ctx context.Context, img image.Image, dimensions []int, width, height int, flipX, flipY, full, isBraille bool, opts PixelOptions, tileRows int, emit func([][]AsciiPixel) error) (AsciiSize, error) {
+
+	if tileRows < 1 {
+		return AsciiSize{}, fmt.Errorf("tile rows must be greater than 0")
+	}
+	if err := checkDitherOptions(opts); err != nil {
+		return AsciiSize{}, err
+	}
+	if err := checkAdjustOptions(opts); err != nil {
+		return AsciiSize{}, err
+	}
+	if err := checkEdgeOptions(opts); err != nil {
+		return AsciiSize{}, err
+	}
+	if err := checkLuminanceOptions(opts); err != nil {
+		return AsciiSize{}, err
+	}
+	if err := checkColorEffectOptions(opts); err != nil {
+		return AsciiSize{}, err
+	}
+	if err := checkQuantizeOptions(opts); err != nil {
+		return AsciiSize{}, err
+	}
+	if err := checkPaletteOptions(opts); err != nil {
+		return AsciiSize{}, err
+	}
+
+	filter, err := ResizeFilter(opts)
+	if err != nil {
+		return AsciiSize{}, err
+	}
+
+	img, err = TransformImage(img, opts)
+	if err != nil {
+		return AsciiSize{}, err
+	}
+	img = coverCrop(img, dimensions, full, opts)
+	b := img.Bounds()
+
+	pixelWidth, pixelHeight, err := resizeDimensions(b, dimensions, width, height, full, isBraille, opts)
+	if err != nil {
+		return AsciiSize{}, err
+	}
+
+	size := asciiSize(pixelWidth, pixelHeight, isBraille, opts)
+	if pixelWidth < 1 || pixelHeight < 1 || b.Empty() {
+		return size, nil
+	}
+
+	// Strips are kept to whole characters, so braille and block characters aren't split between them
+	_, charPixels := resizeForSubPixels(1, 1, isBraille, opts)
+	stripHeight := tileRows * charPixels
+
+	scale := float64(b.Dy()) / float64(pixelHeight)
+	overlap := int(math.Ceil(filter.Support)) + 1
+
+	var starts []int
+	for start := 0; start < pixelHeight; start += stripHeight {
+		starts = append(starts, start)
+	}
+
+	// Flipped ascii art starts from the bottom of the image
+	if flipY {
+		for i, j := 0, len(starts)-1; i < j; i, j = i+1, j-1 {
+			starts[i], starts[j] = starts[j], starts[i]
+		}
+	}
+
+	for _, start := range starts {
+
+		if err := ctx.Err(); err != nil {
+			return AsciiSize{}, err
+		}
+
+		end := start + stripHeight
+		if end > pixelHeight {
+			end = pixelHeight
+		}
+
+		// Rows of the resized image covered by this strip along with its overlap
+		extStart := maxInt(start-overlap, 0)
+		extEnd := minInt(end+overlap, pixelHeight)
+
+		srcStart := int(math.Floor(float64(extStart) * scale))
+		srcEnd := minInt(int(math.Ceil(float64(extEnd)*scale)), b.Dy())
+		if srcEnd <= srcStart {
+			srcEnd = srcStart + 1
+		}
+
+		band := subImage(img, image.Rect(b.Min.X, b.Min.Y+srcStart, b.Max.X, b.Min.Y+srcEnd))
+		resized := resizeWithFilter(band, pixelWidth, extEnd-extStart, filter, opts)
+		strip := subImage(resized, image.Rect(0, start-extStart, pixelWidth, end-extStart))
+
+		imgSet, err := samplePixels(ctx, strip, opts, nil)
+		if err != nil {
+			return AsciiSize{}, err
+		}
+
+		imgSet, err = processPixels(ctx, imgSet, flipX, flipY, opts)
+		if err != nil {
+			return AsciiSize{}, err
+		}
+
+		if err := emit(imgSet); err != nil {
+			return AsciiSize{}, err
+		}
+	}
+
+	return size, nil
+}
+
+// Returns the part of img inside r, without copying it if img supports subimages
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	if subImager, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return subImager.SubImage(r)
+	}
+	return imaging.Crop(img, r)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,174 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/disintegration/imaging"
+)
+
+/*
+Applies the options in opts that change the source image itself, before it's resized by ResizeImage(). Since
+opts.Crop is applied to the full resolution image, the cropped region is shrunk to the same dimensions the
+whole image would have been, instead of being cut out of an already resized image. The image is rotated by
+opts.Rotate after it's cropped, so its new dimensions are used for keeping aspect ratio. If opts.Trim is set, margins
+are trimmed in between, so they're found within the cropped region and opts.Crop is always relative to the original image.
+The result is blurred by opts.Blur and then sharpened by opts.Sharpen last, so sharpening isn't undone by blur.
+
+Images in CMYK color space, as decoded from print-origin jpeg and tiff files, are converted to RGB first.
+
+ConvertToAsciiPixels() calls this on its own, so it's only needed when ResizeImage() is used directly.
+*/
+func TransformImage(img image.Image, opts PixelOptions) (image.Image, error) {
+
+	if cmykImg, ok := img.(*image.CMYK); ok {
+		img = cmykToRGB(cmykImg)
+	}
+
+	if !opts.Crop.Empty() {
+		b := img.Bounds()
+
+		// Crop is relative to the top left corner, which isn't always at 0,0 (e.g. for subimages)
+		region := opts.Crop.Add(b.Min)
+		if !region.In(b) {
+			return nil, fmt.Errorf("crop region %v,%v,%v,%v lies outside image bounds of %vx%v",
+				opts.Crop.Min.X, opts.Crop.Min.Y, opts.Crop.Dx(), opts.Crop.Dy(), b.Dx(), b.Dy())
+		}
+
+		img = imaging.Crop(img, region)
+	}
+
+	if opts.Trim {
+		if opts.TrimTolerance < 0 || opts.TrimTolerance > 255 {
+			return nil, fmt.Errorf("trim tolerance must be between 0 and 255")
+		}
+
+		if region := trimmedRegion(img, uint32(opts.TrimTolerance)); region != img.Bounds() {
+			img = imaging.Crop(img, region)
+		}
+	}
+
+	if opts.Rotate != 0 {
+		background := opts.RotateBackground
+		if background == nil {
+			background = color.Black
+		}
+
+		// imaging rotates counter-clockwise
+		img = imaging.Rotate(img, -opts.Rotate, background)
+	}
+
+	if opts.Blur < 0 {
+		return nil, fmt.Errorf("blur must be greater than 0")
+	}
+	if opts.Blur > 0 {
+		img = imaging.Blur(img, opts.Blur)
+	}
+
+	if opts.Sharpen < 0 {
+		return nil, fmt.Errorf("sharpen must be greater than 0")
+	}
+	if opts.Sharpen > 0 {
+		img = imaging.Sharpen(img, opts.Sharpen)
+	}
+
+	return img, nil
+}
+
+/*
+Returns the bounds of img without the rows and columns around its edges where every pixel is within tolerance of
+the top left one. The full bounds are returned if the whole image is that color, since there'd be nothing left.
+*/
+func trimmedRegion(img image.Image, tolerance uint32) image.Rectangle {
+
+	b := img.Bounds()
+	if b.Empty() {
+		return b
+	}
+
+	reference := img.At(b.Min.X, b.Min.Y)
+
+	uniform := func(r image.Rectangle) bool {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if !similarColor(img.At(x, y), reference, tolerance) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	region := b
+	for region.Min.Y < region.Max.Y && uniform(image.Rect(region.Min.X, region.Min.Y, region.Max.X, region.Min.Y+1)) {
+		region.Min.Y++
+	}
+	if region.Empty() {
+		return b
+	}
+	for uniform(image.Rect(region.Min.X, region.Max.Y-1, region.Max.X, region.Max.Y)) {
+		region.Max.Y--
+	}
+	for uniform(image.Rect(region.Min.X, region.Min.Y, region.Min.X+1, region.Max.Y)) {
+		region.Min.X++
+	}
+	for uniform(image.Rect(region.Max.X-1, region.Min.Y, region.Max.X, region.Max.Y)) {
+		region.Max.X--
+	}
+
+	return region
+}
+
+// Reports whether every channel of c1 and c2, including alpha, is within tolerance of each other on a 0-255 scale
+func similarColor(c1, c2 color.Color, tolerance uint32) bool {
+	r1, g1, b1, a1 := c1.RGBA()
+	r2, g2, b2, a2 := c2.RGBA()
+
+	within := func(v1, v2 uint32) bool {
+		v1, v2 = v1/257, v2/257
+		if v1 > v2 {
+			return v1-v2 <= tolerance
+		}
+		return v2-v1 <= tolerance
+	}
+
+	return within(r1, r2) && within(g1, g2) && within(b1, b2) && within(a1, a2)
+}
+
+/*
+Converts each pixel of passed CMYK image to RGB once, so later steps read RGB pixels directly instead of relying
+on each of them to go through image.CMYK's color model. Decoders have already undone the inverted values that
+Adobe jpegs store, so the conversion is the plain one from color.CMYKToRGB().
+*/
+func cmykToRGB(img *image.CMYK) *image.RGBA {
+
+	b := img.Bounds()
+	rgbImg := image.NewRGBA(b)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.CMYKAt(x, y)
+			r, g, bl := color.CMYKToRGB(c.C, c.M, c.Y, c.K)
+			rgbImg.SetRGBA(x, y, color.RGBA{r, g, bl, 255})
+		}
+	}
+
+	return rgbImg
+}
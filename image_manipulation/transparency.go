@@ -0,0 +1,73 @@
+/*
+Copyright © 2021 Zoraiz Hassan <hzoraiz8@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_conversions
+
+import "image/color"
+
+// Used for PixelOptions.Checkerboard when PixelOptions.CheckerSize or PixelOptions.CheckerColors aren't set
+var (
+	defaultCheckerSize   = 4
+	defaultCheckerColors = [2]color.Color{color.Gray{102}, color.Gray{153}}
+)
+
+// Returns the color that a pixel at x, y of the resized image is blended over, which is one of the squares of
+// the checkerboard if opts.Checkerboard is set, or opts.Background otherwise
+func backgroundAt(x, y int, opts PixelOptions) color.Color {
+	if !opts.Checkerboard {
+		return opts.Background
+	}
+
+	size := opts.CheckerSize
+	if size < 1 {
+		size = defaultCheckerSize
+	}
+
+	square := (x/size + y/size) % 2
+	if opts.CheckerColors[square] != nil {
+		return opts.CheckerColors[square]
+	}
+	return defaultCheckerColors[square]
+}
+
+// Returns whether pixel is transparent enough to be drawn as opts.TransparentChar
+func isTransparent(pixel AsciiPixel, opts CharOptions) bool {
+	return opts.AlphaThreshold > 0 && pixel.alpha < uint32(opts.AlphaThreshold)
+}
+
+// Returns whether every pixel that makes up a single braille, quadrant or half block character is transparent
+func allTransparent(pixels []AsciiPixel, opts CharOptions) bool {
+	for _, pixel := range pixels {
+		if !isTransparent(pixel, opts) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns an uncolored AsciiChar of opts.TransparentChar, or a space if it isn't set
+func transparentChar(opts CharOptions) AsciiChar {
+	simple := opts.TransparentChar
+	if simple == "" {
+		simple = " "
+	}
+
+	return AsciiChar{
+		OriginalColor: simple,
+		SetColor:      simple,
+		Simple:        simple,
+	}
+}